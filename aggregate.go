@@ -0,0 +1,284 @@
+package godyno
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Group - the rows sharing the same values of the group fields; Key holds those values
+type Group struct {
+	Key  *DBResult
+	Rows ResultSet
+}
+
+// Groups - groups in the order their first row appeared
+type Groups []Group
+
+// Aggregation - an aggregate function over a field, stored in the result as Name
+type Aggregation struct {
+	Name  string
+	Field string
+	Func  func(rs ResultSet, field string) any
+}
+
+// As - returns the aggregation with a different result field name
+func (a Aggregation) As(name string) Aggregation {
+	a.Name = name
+	return a
+}
+
+// Sum - sums the numeric values of a field; int64 for integer fields, float64
+// otherwise, NULL when there are none
+func Sum(field string) Aggregation {
+	return newAggregation("sum", field, func(rs ResultSet, field string) any {
+		return rs.Sum(field)
+	})
+}
+
+// Avg - averages the numeric values of a field; NULL when there are none
+func Avg(field string) Aggregation {
+	return newAggregation("avg", field, func(rs ResultSet, field string) any {
+		avg, ok := rs.Avg(field)
+		if !ok {
+			return nil
+		}
+		return avg
+	})
+}
+
+// Min - returns the smallest value of a field
+func Min(field string) Aggregation {
+	return newAggregation("min", field, func(rs ResultSet, field string) any {
+		return rs.Min(field)
+	})
+}
+
+// Max - returns the largest value of a field
+func Max(field string) Aggregation {
+	return newAggregation("max", field, func(rs ResultSet, field string) any {
+		return rs.Max(field)
+	})
+}
+
+// Count - counts the non-NULL values of a field, or the rows when field is ""
+func Count(field string) Aggregation {
+	return newAggregation("count", field, func(rs ResultSet, field string) any {
+		return rs.Count(field)
+	})
+}
+
+// CountDistinct - counts the distinct non-NULL values of a field
+func CountDistinct(field string) Aggregation {
+	return newAggregation("count_distinct", field, func(rs ResultSet, field string) any {
+		return rs.CountDistinct(field)
+	})
+}
+
+// newAggregation - creates an aggregation named like "sum_price"
+func newAggregation(fn, field string, f func(ResultSet, string) any) Aggregation {
+	name := fn
+	if field != "" {
+		name += "_" + strings.ReplaceAll(field, ".", "_")
+	}
+	return Aggregation{Name: name, Field: field, Func: f}
+}
+
+// GroupBy - groups the rows by the values of the given fields
+func (rs ResultSet) GroupBy(fields ...string) Groups {
+	var groups Groups
+	index := make(map[string]int)
+
+	for _, dr := range rs {
+		var key strings.Builder
+		for _, f := range fields {
+			val := fieldValue(dr, f) // NULL must not share a group with 0 or ""
+			fmt.Fprintf(&key, "%T:%v\x00", val, val)
+		}
+
+		i, ok := index[key.String()]
+		if !ok {
+			i = len(groups)
+			index[key.String()] = i
+			groups = append(groups, Group{Key: groupKey(dr, fields)})
+		}
+		groups[i].Rows = append(groups[i].Rows, dr)
+	}
+
+	return groups
+}
+
+// groupKey - builds the key result holding the group field values of a row
+func groupKey(dr *DBResult, fields []string) *DBResult {
+	entries := make([]entry, 0, len(fields))
+	for _, f := range fields {
+		entries = append(entries, valueEntry(dr, f))
+	}
+
+	key, err := buildResult(entries)
+	if err != nil {
+		return New()
+	}
+	return key
+}
+
+// valueEntry - returns a field of a row as an entry, keeping NULL
+func valueEntry(dr *DBResult, field string) entry {
+	e := entry{column: field, typ: fieldType(dr, field), value: dr.Get(field)}
+	if dr.IsNull(field) {
+		e.value = nil
+	}
+	return e
+}
+
+// fieldType - returns the type of a field, string when it doesn't exist
+func fieldType(dr *DBResult, field string) reflect.Type {
+	if val := dr.Get(field); val != nil {
+		return reflect.TypeOf(val)
+	}
+	return stringType
+}
+
+// Aggregate - returns one row per group holding the group fields and the aggregations.
+// Every row shares one type, so a group where an aggregation is NULL keeps the
+// type the other groups give that field.
+func (g Groups) Aggregate(aggs ...Aggregation) (ResultSet, error) {
+	rows := make([][]entry, 0, len(g))
+	for _, group := range g {
+		entries := group.Key.entries()
+		rows = append(rows, append(entries, group.Rows.aggregateEntries(aggs)...))
+	}
+	return buildSet(rows)
+}
+
+// Aggregate - returns a single row holding the aggregations over the whole set
+func (rs ResultSet) Aggregate(aggs ...Aggregation) (*DBResult, error) {
+	return buildResult(rs.aggregateEntries(aggs))
+}
+
+// aggregateEntries - evaluates the aggregations as entries
+func (rs ResultSet) aggregateEntries(aggs []Aggregation) []entry {
+	entries := make([]entry, 0, len(aggs))
+	for _, a := range aggs {
+		value := a.Func(rs, a.Field)
+
+		typ := stringType
+		if value != nil {
+			typ = reflect.TypeOf(value)
+		} else if first := rs.First(); first != nil {
+			typ = fieldType(first, a.Field)
+		}
+		entries = append(entries, entry{column: a.Name, typ: typ, value: value})
+	}
+	return entries
+}
+
+// Sum - sums the numeric values of a field; int64 for integer fields, float64
+// otherwise, nil when there are none
+func (rs ResultSet) Sum(field string) any {
+	values := rs.numbers(field)
+	if len(values) == 0 {
+		return nil
+	}
+
+	var ints int64
+	var floats float64
+	isFloatSum := false
+
+	for _, v := range values {
+		if isFloat(v.Type()) {
+			isFloatSum = true
+			floats += v.Float()
+			continue
+		}
+		ints += toInt64(v)
+	}
+
+	if isFloatSum {
+		return floats + float64(ints)
+	}
+	return ints
+}
+
+// Avg - averages the numeric values of a field; ok is false when there are none
+func (rs ResultSet) Avg(field string) (float64, bool) {
+	values := rs.numbers(field)
+	if len(values) == 0 {
+		return 0, false
+	}
+
+	var total float64
+	for _, v := range values {
+		total += toFloat64(v)
+	}
+	return total / float64(len(values)), true
+}
+
+// Min - returns the smallest non-NULL value of a field, or nil
+func (rs ResultSet) Min(field string) any {
+	return rs.extreme(field, -1)
+}
+
+// Max - returns the largest non-NULL value of a field, or nil
+func (rs ResultSet) Max(field string) any {
+	return rs.extreme(field, 1)
+}
+
+// Count - counts the non-NULL values of a field, or the rows when field is ""
+func (rs ResultSet) Count(field string) int {
+	if field == "" {
+		return len(rs)
+	}
+
+	n := 0
+	for _, dr := range rs {
+		if dr.Has(field) && !dr.IsNull(field) {
+			n++
+		}
+	}
+	return n
+}
+
+// CountDistinct - counts the distinct non-NULL values of a field
+func (rs ResultSet) CountDistinct(field string) int {
+	seen := make(map[string]bool)
+	for _, dr := range rs {
+		if !dr.Has(field) || dr.IsNull(field) {
+			continue
+		}
+		seen[fmt.Sprintf("%T:%v", dr.Get(field), dr.Get(field))] = true
+	}
+	return len(seen)
+}
+
+// numbers - returns the non-NULL numeric values of a field
+func (rs ResultSet) numbers(field string) []reflect.Value {
+	var values []reflect.Value
+	for _, dr := range rs {
+		val := dr.Get(field)
+		if val == nil || dr.IsNull(field) || !isNumeric(reflect.TypeOf(val)) {
+			continue
+		}
+		values = append(values, reflect.ValueOf(val))
+	}
+	return values
+}
+
+// extreme - returns the smallest (dir -1) or largest (dir 1) value of a field
+func (rs ResultSet) extreme(field string, dir int) any {
+	var best any
+	for _, dr := range rs {
+		val := dr.Get(field)
+		if val == nil || dr.IsNull(field) {
+			continue
+		}
+		if best == nil {
+			best = val
+			continue
+		}
+		if c, ok := compareValues(val, best); ok && c*dir > 0 {
+			best = val
+		}
+	}
+	return best
+}
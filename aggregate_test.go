@@ -0,0 +1,117 @@
+package godyno
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGroupByAggregate(t *testing.T) {
+	rs := productSet(t)
+
+	groups := rs.GroupBy("category")
+	if len(groups) != 2 {
+		t.Fatalf("GroupBy() returned %d groups, want 2", len(groups))
+	}
+	if groups[0].Key.GetString("category") != "tech" || len(groups[0].Rows) != 2 {
+		t.Errorf("First group = %v with %d rows", groups[0].Key.ToMap(), len(groups[0].Rows))
+	}
+
+	totals, err := groups.Aggregate(
+		Count(""),
+		Sum("price").As("total"),
+		Avg("price"),
+		Min("price"),
+		Max("title"),
+		Sum("id"),
+		CountDistinct("price"),
+	)
+	if err != nil {
+		t.Fatalf("Aggregate() error = %v", err)
+	}
+
+	got, err := json.Marshal(totals)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	want := `[{"category":"tech","count":2,"total":69.8,"avg_price":34.9,"min_price":19.9,"max_title":"Mouse","sum_id":4,"count_distinct_price":2},` +
+		`{"category":"home","count":2,"total":218.9,"avg_price":109.45,"min_price":19.9,"max_title":"Lamp","sum_id":6,"count_distinct_price":2}]`
+	if string(got) != want {
+		t.Errorf("Aggregate() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestResultSetAggregates(t *testing.T) {
+	rs := newTestSet(t,
+		map[string]any{"qty": int64(2), "note": "a"},
+		map[string]any{"qty": int64(5), "note": nil},
+		map[string]any{"qty": nil, "note": "a"},
+	)
+
+	if got := rs.Sum("qty"); got != int64(7) {
+		t.Errorf("Sum(qty) = %#v, want int64(7)", got)
+	}
+	if got, ok := rs.Avg("qty"); !ok || got != 3.5 {
+		t.Errorf("Avg(qty) = %v, %v, want 3.5", got, ok)
+	}
+	if rs.Count("note") != 2 || rs.Count("") != 3 || rs.CountDistinct("note") != 1 {
+		t.Errorf("Count(note) = %d, Count() = %d, CountDistinct(note) = %d", rs.Count("note"), rs.Count(""), rs.CountDistinct("note"))
+	}
+	if rs.Min("qty") != int64(2) || rs.Max("qty") != int64(5) {
+		t.Errorf("Min(qty) = %v, Max(qty) = %v", rs.Min("qty"), rs.Max("qty"))
+	}
+	if _, ok := (ResultSet{}).Avg("qty"); ok {
+		t.Error("Avg() of an empty set should not be ok")
+	}
+
+	total, err := rs.Aggregate(Sum("qty").As("total"), Max("missing"))
+	if err != nil {
+		t.Fatalf("Aggregate() error = %v", err)
+	}
+	if total.GetInt("total") != 7 || !total.IsNull("max_missing") {
+		t.Errorf("Aggregate() = %v", total.ToMap())
+	}
+}
+
+func TestGroupByNull(t *testing.T) {
+	rs := newTestSet(t,
+		map[string]any{"id": int64(1), "qty": int64(5)},
+		map[string]any{"id": int64(2), "qty": nil},
+		map[string]any{"id": int64(3), "qty": int64(0)},
+		map[string]any{"id": int64(4), "qty": nil},
+	)
+
+	groups := rs.GroupBy("qty")
+	if len(groups) != 3 {
+		t.Fatalf("GroupBy(qty) returned %d groups, want 3", len(groups))
+	}
+	if !groups[1].Key.IsNull("qty") || len(groups[1].Rows) != 2 {
+		t.Errorf("NULL group = %v with %d rows", groups[1].Key.ToMap(), len(groups[1].Rows))
+	}
+	if groups[2].Key.IsNull("qty") || groups[2].Key.GetInt("qty") != 0 || len(groups[2].Rows) != 1 {
+		t.Errorf("Zero group = %v with %d rows", groups[2].Key.ToMap(), len(groups[2].Rows))
+	}
+}
+
+func TestGroupsAggregateNullGroup(t *testing.T) {
+	rs := newTestSet(t,
+		map[string]any{"category": "tech", "price": 10.5},
+		map[string]any{"category": "home", "price": nil},
+	)
+
+	totals, err := rs.GroupBy("category").Aggregate(Sum("price"), Avg("price"), Count(""))
+	if err != nil {
+		t.Fatalf("Aggregate() error = %v", err)
+	}
+
+	got, _ := json.Marshal(totals)
+	want := `[{"category":"tech","sum_price":10.5,"avg_price":10.5,"count":1},{"category":"home","sum_price":null,"avg_price":null,"count":1}]`
+	if string(got) != want {
+		t.Errorf("Aggregate() = %s, want %s", got, want)
+	}
+	if totals[0].Type() != totals[1].Type() {
+		t.Errorf("Aggregate() rows have different types: %v and %v", totals[0].Type(), totals[1].Type())
+	}
+	if got := rs[1:].Sum("price"); got != nil {
+		t.Errorf("Sum() of NULLs = %#v, want nil", got)
+	}
+}
@@ -0,0 +1,296 @@
+// Command godyno runs a query through godyno and prints the results.
+//
+// Usage:
+//
+//	godyno [flags] <query | file.sql> [args...]
+//
+// The DSN is taken from -dsn or the GODYNO_DSN environment variable, the
+// driver from -driver or GODYNO_DRIVER ("postgres" by default). Query
+// arguments are passed to the database as strings.
+//
+// Examples:
+//
+//	godyno -dsn "$PG" "SELECT * FROM products WHERE category_id = \$1" 5
+//	godyno -format csv reports/monthly.sql 2024-01 > monthly.csv
+//	godyno -describe "SELECT * FROM products"
+//	godyno -format go -package models -type Product "SELECT * FROM products"
+//
+// "godyno repl [flags]" starts an interactive session instead, see repl.go.
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/mstgnz/godyno"
+)
+
+// formats - the supported output formats
+var formats = []string{"json", "ndjson", "csv", "table", "go"}
+
+// options - the parsed command line
+type options struct {
+	driver   string
+	dsn      string
+	format   string
+	describe bool
+	sample   int
+	pkg      string
+	typeName string
+	query    string
+	args     []any
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr, os.Getenv))
+}
+
+// run - executes the command and returns the exit code
+func run(argv []string, stdin io.Reader, stdout, stderr io.Writer, getenv func(string) string) int {
+	if len(argv) > 0 && argv[0] == "repl" {
+		return runREPL(argv[1:], stdin, stdout, stderr, getenv)
+	}
+
+	opts, err := parseArgs(argv, stderr, getenv)
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "godyno: %v\n", err)
+		return 2
+	}
+
+	db, err := sql.Open(opts.driver, opts.dsn)
+	if err != nil {
+		fmt.Fprintf(stderr, "godyno: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	if err := execute(db, opts, stdout); err != nil {
+		fmt.Fprintf(stderr, "godyno: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// parseArgs - parses flags, reads the query (or .sql file) and collects the query arguments
+func parseArgs(argv []string, stderr io.Writer, getenv func(string) string) (*options, error) {
+	opts := &options{}
+
+	fs := flag.NewFlagSet("godyno", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	connectionFlags(fs, opts, getenv)
+	fs.StringVar(&opts.format, "format", "json", "output format: "+strings.Join(formats, "|"))
+	fs.BoolVar(&opts.describe, "describe", false, "print the inferred field names and types instead of the rows")
+	fs.StringVar(&opts.pkg, "package", "main", "package name for -format go")
+	fs.StringVar(&opts.typeName, "type", "Result", "type name for -format go")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: godyno [flags] <query | file.sql> [args...]")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(argv); err != nil {
+		return nil, err
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return nil, errors.New("missing query")
+	}
+	if opts.dsn == "" {
+		return nil, errMissingDSN
+	}
+	if !slices.Contains(formats, opts.format) {
+		return nil, fmt.Errorf("unknown format %q, want one of %s", opts.format, strings.Join(formats, ", "))
+	}
+
+	opts.query = fs.Arg(0)
+	if strings.HasSuffix(opts.query, ".sql") {
+		b, err := os.ReadFile(opts.query)
+		if err != nil {
+			return nil, err
+		}
+		opts.query = strings.TrimSpace(string(b))
+	}
+	for _, arg := range fs.Args()[1:] {
+		opts.args = append(opts.args, arg)
+	}
+
+	return opts, nil
+}
+
+// errMissingDSN - returned when neither -dsn nor GODYNO_DSN is set
+var errMissingDSN = errors.New("missing DSN: use -dsn or GODYNO_DSN")
+
+// connectionFlags - defines the flags shared by the query and repl commands
+func connectionFlags(fs *flag.FlagSet, opts *options, getenv func(string) string) {
+	fs.StringVar(&opts.driver, "driver", envOr(getenv, "GODYNO_DRIVER", "postgres"), "database/sql driver name (env GODYNO_DRIVER)")
+	fs.StringVar(&opts.dsn, "dsn", getenv("GODYNO_DSN"), "data source name (env GODYNO_DSN)")
+	fs.IntVar(&opts.sample, "sample", 1, "rows sampled for type inference, 0 samples all rows")
+}
+
+// execute - runs the query and writes the output
+func execute(db *sql.DB, opts *options, w io.Writer) error {
+	if opts.describe {
+		return describeQuery(w, db, opts.query, opts.sample, opts.args)
+	}
+
+	args := append(opts.args, godyno.WithSampleRows(opts.sample))
+
+	// streaming formats do not hold the result in memory
+	switch opts.format {
+	case "ndjson":
+		enc := godyno.NewNDJSONEncoder(w)
+		enc.FlushEvery = 0
+		if err := godyno.QueryEach(db, opts.query, enc.Encode, args...); err != nil {
+			return err
+		}
+		return enc.Flush()
+	case "csv":
+		cw := godyno.NewCSVWriter(w)
+		if err := godyno.QueryEach(db, opts.query, cw.Write, args...); err != nil {
+			return err
+		}
+		return cw.Flush()
+	}
+
+	results, err := godyno.QueryToStruct(db, opts.query, args...)
+	if err != nil {
+		return err
+	}
+
+	if opts.format == "go" {
+		if len(results) == 0 {
+			return errors.New("query returned no rows, field types cannot be inferred")
+		}
+		src, err := godyno.GenerateStruct(opts.pkg, opts.typeName, results[0])
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(src)
+		return err
+	}
+
+	return render(w, opts.format, results)
+}
+
+// render - writes results in one of the json, ndjson, csv or table formats
+func render(w io.Writer, format string, results godyno.ResultSet) error {
+	switch format {
+	case "ndjson":
+		return godyno.NewNDJSONEncoder(w).EncodeAll(results)
+	case "csv":
+		return godyno.NewCSVWriter(w).WriteAll(results)
+	case "table":
+		return godyno.NewTableWriter(w).WriteAll(results)
+	}
+
+	if results == nil {
+		results = godyno.ResultSet{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(results)
+}
+
+// describeQuery - prints the inferred fields of a query. A query without rows has
+// nothing to infer from, so it is run again for the column types the driver reports.
+func describeQuery(w io.Writer, db *sql.DB, query string, sample int, args []any) error {
+	results, err := godyno.QueryToStruct(db, query, append(args, godyno.WithSampleRows(sample))...)
+	if err != nil {
+		return err
+	}
+	if len(results) > 0 {
+		return describe(w, results)
+	}
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return err
+	}
+	if err := describeColumns(w, columnTypes); err != nil {
+		return err
+	}
+	return rows.Err()
+}
+
+// describe - prints the inferred fields of the result
+func describe(w io.Writer, results godyno.ResultSet) error {
+	if len(results) == 0 {
+		return errors.New("query returned no rows, field types cannot be inferred")
+	}
+
+	dr := results[0]
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "COLUMN\tFIELD\tTYPE\tDATABASE TYPE\tNULLABLE")
+	for _, f := range dr.Fields() {
+		dbType := ""
+		if col, ok := dr.Metadata().Column(f.Column); ok {
+			dbType = col.DatabaseType
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%t\n", f.Column, goPath(dr.Type(), f.Path), f.Type, dbType, f.Nullable)
+	}
+	return tw.Flush()
+}
+
+// describeColumns - prints the column types reported by the driver, for results without rows
+func describeColumns(w io.Writer, columnTypes []*sql.ColumnType) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "COLUMN\tFIELD\tTYPE\tDATABASE TYPE\tNULLABLE")
+	for _, ct := range columnTypes {
+		typ := "unknown"
+		if st := ct.ScanType(); st != nil {
+			typ = st.String()
+		}
+		nullable := "unknown"
+		if n, ok := ct.Nullable(); ok {
+			nullable = strconv.FormatBool(n)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", ct.Name(), "-", typ, ct.DatabaseTypeName(), nullable)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w, "(no rows, types reported by the driver)")
+	return err
+}
+
+// goPath - returns the Go selector of a nested field, e.g. "Address.City"
+func goPath(t reflect.Type, path []string) string {
+	names := make([]string, 0, len(path))
+	for _, key := range path {
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if name, _, _ := strings.Cut(sf.Tag.Get("json"), ","); name == key {
+				names = append(names, sf.Name)
+				t = sf.Type
+				break
+			}
+		}
+	}
+	return strings.Join(names, ".")
+}
+
+// envOr - returns the environment variable or def when it is empty
+func envOr(getenv func(string) string, key, def string) string {
+	if v := getenv(key); v != "" {
+		return v
+	}
+	return def
+}
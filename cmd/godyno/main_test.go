@@ -0,0 +1,173 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// setupMock - registers a sqlmock connection under a unique DSN for the command to open
+func setupMock(t *testing.T) (string, sqlmock.Sqlmock) {
+	t.Helper()
+
+	dsn := "godyno-" + t.Name()
+	db, mock, err := sqlmock.NewWithDSN(dsn)
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return dsn, mock
+}
+
+// productRows - the rows returned by the mocked products query
+func productRows() *sqlmock.Rows {
+	return sqlmock.NewRows([]string{"id", "title", "address.city"}).
+		AddRow(1, "Desk", "Izmir").
+		AddRow(2, "Lamp", nil)
+}
+
+// runCommand - runs the command with GODYNO_DRIVER set to sqlmock
+func runCommand(t *testing.T, env map[string]string, args ...string) (code int, stdout, stderr string) {
+	t.Helper()
+
+	var out, errOut strings.Builder
+	getenv := func(key string) string {
+		if key == "GODYNO_DRIVER" {
+			return "sqlmock"
+		}
+		return env[key]
+	}
+	code = run(args, strings.NewReader(""), &out, &errOut, getenv)
+	return code, out.String(), errOut.String()
+}
+
+func TestRunFormats(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{"json", "[\n  {\n    \"id\": 1,\n    \"title\": \"Desk\",\n    \"address\": {\n      \"city\": \"Izmir\"\n    }\n  },\n" +
+			"  {\n    \"id\": 2,\n    \"title\": \"Lamp\",\n    \"address\": {\n      \"city\": null\n    }\n  }\n]\n"},
+		{"ndjson", `{"id":1,"title":"Desk","address":{"city":"Izmir"}}` + "\n" + `{"id":2,"title":"Lamp","address":{"city":null}}` + "\n"},
+		{"csv", "id,title,address.city\n1,Desk,Izmir\n2,Lamp,\n"},
+		{"table", "+----+-------+--------------+\n| id | title | address.city |\n+----+-------+--------------+\n" +
+			"|  1 | Desk  | Izmir        |\n|  2 | Lamp  | NULL         |\n+----+-------+--------------+\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			dsn, mock := setupMock(t)
+			mock.ExpectQuery("SELECT (.+) FROM products WHERE category_id = \\$1").WithArgs("5").WillReturnRows(productRows())
+
+			code, stdout, stderr := runCommand(t, nil, "-dsn", dsn, "-format", tt.format, "SELECT * FROM products WHERE category_id = $1", "5")
+			if code != 0 {
+				t.Fatalf("run() = %d, stderr: %s", code, stderr)
+			}
+			if stdout != tt.want {
+				t.Errorf("Output =\n%s\nwant\n%s", stdout, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("Unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+
+func TestRunDescribeAndGo(t *testing.T) {
+	t.Run("describe", func(t *testing.T) {
+		dsn, mock := setupMock(t)
+		mock.ExpectQuery("SELECT").WillReturnRows(productRows())
+
+		code, stdout, stderr := runCommand(t, map[string]string{"GODYNO_DSN": dsn}, "-describe", "-sample", "0", "SELECT * FROM products")
+		if code != 0 {
+			t.Fatalf("run() = %d, stderr: %s", code, stderr)
+		}
+		for _, want := range []string{"COLUMN", "id            Id            int64", "address.city  Address.City  string", "true"} {
+			if !strings.Contains(stdout, want) {
+				t.Errorf("Describe output does not contain %q:\n%s", want, stdout)
+			}
+		}
+	})
+
+	t.Run("describe without rows", func(t *testing.T) {
+		dsn, mock := setupMock(t)
+		columns := []*sqlmock.Column{
+			sqlmock.NewColumn("id").OfType("INT8", int64(0)).Nullable(false),
+			sqlmock.NewColumn("title").OfType("TEXT", ""),
+		}
+		for range 2 {
+			mock.ExpectQuery("SELECT id, title FROM products").WithArgs("7").WillReturnRows(sqlmock.NewRowsWithColumnDefinition(columns...))
+		}
+
+		code, stdout, stderr := runCommand(t, nil, "-dsn", dsn, "-describe", "SELECT id, title FROM products WHERE id = $1", "7")
+		if code != 0 {
+			t.Fatalf("run() = %d, stderr: %s", code, stderr)
+		}
+		want := "COLUMN  FIELD  TYPE    DATABASE TYPE  NULLABLE\n" +
+			"id      -      int64   INT8           false\n" +
+			"title   -      string  TEXT           unknown\n" +
+			"(no rows, types reported by the driver)\n"
+		if stdout != want {
+			t.Errorf("Describe output =\n%s\nwant\n%s", stdout, want)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("go from sql file", func(t *testing.T) {
+		dsn, mock := setupMock(t)
+		mock.ExpectQuery("SELECT id, title").WillReturnRows(productRows())
+
+		file := filepath.Join(t.TempDir(), "products.sql")
+		if err := os.WriteFile(file, []byte("SELECT id, title, city AS \"address.city\" FROM products;\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+
+		code, stdout, stderr := runCommand(t, nil, "-dsn", dsn, "-format", "go", "-package", "models", "-type", "Product", file)
+		if code != 0 {
+			t.Fatalf("run() = %d, stderr: %s", code, stderr)
+		}
+		for _, want := range []string{"package models", "type Product struct", "type ProductAddress struct", "func ScanProduct("} {
+			if !strings.Contains(stdout, want) {
+				t.Errorf("Generated code does not contain %q:\n%s", want, stdout)
+			}
+		}
+	})
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		code int
+		want string
+	}{
+		{"missing query", []string{"-dsn", "x"}, 2, "missing query"},
+		{"missing dsn", []string{"SELECT 1"}, 2, "missing DSN"},
+		{"unknown format", []string{"-dsn", "x", "-format", "yaml", "SELECT 1"}, 2, `unknown format "yaml"`},
+		{"missing file", []string{"-dsn", "x", "nope.sql"}, 2, "nope.sql"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, stderr := runCommand(t, nil, tt.args...)
+			if code != tt.code || !strings.Contains(stderr, tt.want) {
+				t.Errorf("run() = %d, stderr %q; want %d containing %q", code, stderr, tt.code, tt.want)
+			}
+		})
+	}
+
+	t.Run("query error", func(t *testing.T) {
+		dsn, mock := setupMock(t)
+		mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+		code, _, stderr := runCommand(t, nil, "-dsn", dsn, "-format", "go", "SELECT id FROM empty")
+		if code != 1 || !strings.Contains(stderr, "no rows") {
+			t.Errorf("run() = %d, stderr %q", code, stderr)
+		}
+	})
+}
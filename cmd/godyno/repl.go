@@ -0,0 +1,310 @@
+package main
+
+import (
+	"bufio"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/mstgnz/godyno"
+)
+
+// replFormats - the output formats selectable with \format
+var replFormats = []string{"table", "json", "ndjson", "csv"}
+
+// tableName - a table name accepted by \d, optionally schema qualified
+var tableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*(\.[A-Za-z_][A-Za-z0-9_]*)?$`)
+
+// describeLimit - the number of rows \d samples to infer the field types
+const describeLimit = 100
+
+const replHelp = `Statements end with ";" and may span several lines; a ";" inside quotes,
+$$ bodies or comments does not end them.
+  \d TABLE                 describe the inferred fields of a table
+  \timing                  toggle query timing
+  \format json|csv|table   set the output format (ndjson too)
+  \save FILE               save the last result; .json, .ndjson, .csv and .xlsx pick the format
+  \?                       show this help
+  \q                       quit
+`
+
+// repl - an interactive session on an open connection
+type repl struct {
+	db     *sql.DB
+	out    io.Writer
+	format string
+	timing bool
+	sample int
+	last   godyno.ResultSet
+}
+
+// runREPL - parses the repl flags, connects and reads statements until \q or end of input
+func runREPL(argv []string, stdin io.Reader, stdout, stderr io.Writer, getenv func(string) string) int {
+	opts := &options{}
+
+	fs := flag.NewFlagSet("godyno repl", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	connectionFlags(fs, opts, getenv)
+	fs.StringVar(&opts.format, "format", "table", "output format: "+strings.Join(replFormats, "|"))
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: godyno repl [flags]")
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(argv); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	if opts.dsn == "" {
+		fmt.Fprintf(stderr, "godyno: %v\n", errMissingDSN)
+		return 2
+	}
+	if !slices.Contains(replFormats, opts.format) {
+		fmt.Fprintf(stderr, "godyno: unknown format %q, want one of %s\n", opts.format, strings.Join(replFormats, ", "))
+		return 2
+	}
+
+	db, err := sql.Open(opts.driver, opts.dsn)
+	if err == nil {
+		err = db.Ping()
+	}
+	if err != nil {
+		fmt.Fprintf(stderr, "godyno: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	r := &repl{db: db, out: stdout, format: opts.format, sample: opts.sample}
+	r.loop(stdin)
+	return 0
+}
+
+// loop - reads lines, running meta-commands immediately and statements once a ";" ends them
+func (r *repl) loop(in io.Reader) {
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var sp splitter
+	fmt.Fprint(r.out, "godyno> ")
+	for scanner.Scan() {
+		line := scanner.Text()
+
+		if !sp.pending() && strings.HasPrefix(strings.TrimSpace(line), `\`) {
+			if !r.meta(strings.TrimSpace(line)) {
+				return
+			}
+		} else {
+			for _, stmt := range sp.feed(line) {
+				r.run(stmt)
+			}
+		}
+
+		if sp.pending() {
+			fmt.Fprint(r.out, "   ...> ")
+		} else {
+			fmt.Fprint(r.out, "godyno> ")
+		}
+	}
+	fmt.Fprintln(r.out)
+}
+
+// splitter - collects input lines into statements. A ";" ends a statement
+// unless it is inside a quoted literal or identifier, a $tag$ dollar-quoted
+// body, or a "--" or "/* */" comment.
+type splitter struct {
+	buf   strings.Builder
+	quote string // the delimiter closing the open literal or comment
+}
+
+// dollarTag - a dollar quote opening a Postgres function body, e.g. $$ or $body$
+var dollarTag = regexp.MustCompile(`^\$([A-Za-z_][A-Za-z0-9_]*)?\$`)
+
+// feed - adds a line and returns the statements it completes
+func (sp *splitter) feed(line string) []string {
+	if sp.quote == "" {
+		line = strings.TrimSpace(line)
+	}
+	if sp.pending() {
+		sp.buf.WriteByte('\n')
+	} else {
+		sp.buf.Reset()
+	}
+
+	var stmts []string
+	for i := 0; i < len(line); {
+		open := ""
+		switch c := line[i]; {
+		case sp.quote != "":
+			// A doubled quote closes and reopens the literal, so it needs no special case
+			if strings.HasPrefix(line[i:], sp.quote) {
+				sp.buf.WriteString(sp.quote)
+				i += len(sp.quote)
+				sp.quote = ""
+				continue
+			}
+		case c == '\'' || c == '"':
+			open, sp.quote = line[i:i+1], line[i:i+1]
+		case strings.HasPrefix(line[i:], "/*"):
+			open, sp.quote = "/*", "*/"
+		case c == '$' && (i == 0 || !isWordByte(line[i-1])):
+			if tag := dollarTag.FindString(line[i:]); tag != "" {
+				open, sp.quote = tag, tag
+			}
+		case strings.HasPrefix(line[i:], "--"):
+			rest := strings.TrimRight(sp.buf.String(), " \t")
+			sp.buf.Reset()
+			sp.buf.WriteString(rest)
+			return stmts
+		case c == ';':
+			if stmt := strings.TrimSpace(sp.buf.String()); stmt != "" {
+				stmts = append(stmts, stmt)
+			}
+			sp.buf.Reset()
+			i++
+			continue
+		}
+
+		if open != "" {
+			sp.buf.WriteString(open)
+			i += len(open)
+			continue
+		}
+		sp.buf.WriteByte(line[i])
+		i++
+	}
+	return stmts
+}
+
+// isWordByte - reports whether b can be part of an identifier, so a "$" after it is not a dollar quote
+func isWordByte(b byte) bool {
+	return b == '_' || b == '$' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// pending - reports whether an unfinished statement is buffered
+func (sp *splitter) pending() bool {
+	return sp.quote != "" || strings.TrimSpace(sp.buf.String()) != ""
+}
+
+// meta - runs a meta-command and reports whether the session continues
+func (r *repl) meta(line string) bool {
+	cmd, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+
+	switch cmd {
+	case `\q`, `\quit`:
+		return false
+	case `\?`, `\h`, `\help`:
+		fmt.Fprint(r.out, replHelp)
+	case `\timing`:
+		r.timing = !r.timing
+		fmt.Fprintf(r.out, "Timing is %s.\n", onOff(r.timing))
+	case `\format`:
+		switch {
+		case arg == "":
+			fmt.Fprintf(r.out, "Output format is %s.\n", r.format)
+		case slices.Contains(replFormats, arg):
+			r.format = arg
+			fmt.Fprintf(r.out, "Output format is %s.\n", r.format)
+		default:
+			r.errorf("unknown format %q, want one of %s", arg, strings.Join(replFormats, ", "))
+		}
+	case `\d`:
+		if !tableName.MatchString(arg) {
+			r.errorf(`\d needs a table name, e.g. \d products`)
+			break
+		}
+		if err := r.describeTable(arg); err != nil {
+			r.errorf("%v", err)
+		}
+	case `\save`:
+		if arg == "" {
+			r.errorf(`\save needs a file name`)
+			break
+		}
+		if err := r.save(arg); err != nil {
+			r.errorf("%v", err)
+			break
+		}
+		fmt.Fprintf(r.out, "Saved %d rows to %s.\n", len(r.last), arg)
+	default:
+		r.errorf(`unknown command %s, try \?`, cmd)
+	}
+	return true
+}
+
+// describeTable - prints the inferred fields of a table
+func (r *repl) describeTable(table string) error {
+	return describeQuery(r.out, r.db, fmt.Sprintf("SELECT * FROM %s LIMIT %d", table, describeLimit), 0, nil)
+}
+
+// run - executes a statement and prints its result
+func (r *repl) run(query string) {
+	start := time.Now()
+	results, err := godyno.QueryToStruct(r.db, query, godyno.WithSampleRows(r.sample))
+	elapsed := time.Since(start)
+	if err != nil {
+		r.errorf("%v", err)
+		return
+	}
+	r.last = results
+
+	if len(results) > 0 {
+		if err := render(r.out, r.format, results); err != nil {
+			r.errorf("%v", err)
+		}
+	}
+	if r.format == "table" {
+		if len(results) == 1 {
+			fmt.Fprintln(r.out, "(1 row)")
+		} else {
+			fmt.Fprintf(r.out, "(%d rows)\n", len(results))
+		}
+	}
+	if r.timing {
+		fmt.Fprintf(r.out, "Time: %.3f ms\n", float64(elapsed.Microseconds())/1000)
+	}
+}
+
+// save - writes the last result to a file, the format taken from the extension
+func (r *repl) save(name string) (err error) {
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}()
+
+	switch ext := strings.TrimPrefix(filepath.Ext(name), "."); ext {
+	case "xlsx":
+		return godyno.NewXLSXWriter(f).WriteAll(r.last)
+	case "json", "ndjson", "csv":
+		return render(f, ext, r.last)
+	}
+	return render(f, r.format, r.last)
+}
+
+// errorf - prints an error and keeps the session going
+func (r *repl) errorf(format string, args ...any) {
+	fmt.Fprintf(r.out, "error: "+format+"\n", args...)
+}
+
+// onOff - returns "on" or "off"
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
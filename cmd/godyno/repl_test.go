@@ -0,0 +1,152 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// runSession - runs the repl with input as stdin and returns the output
+func runSession(t *testing.T, dsn, input string) string {
+	t.Helper()
+
+	var out, errOut strings.Builder
+	getenv := func(key string) string {
+		if key == "GODYNO_DRIVER" {
+			return "sqlmock"
+		}
+		return ""
+	}
+	if code := run([]string{"repl", "-dsn", dsn}, strings.NewReader(input), &out, &errOut, getenv); code != 0 {
+		t.Fatalf("run(repl) = %d, stderr: %s", code, errOut.String())
+	}
+	return out.String()
+}
+
+func TestREPL(t *testing.T) {
+	dsn, mock := setupMock(t)
+	mock.ExpectQuery("SELECT id, title, city AS \"address.city\"\nFROM products$").WillReturnRows(productRows())
+	mock.ExpectQuery("SELECT \\* FROM products LIMIT 100").WillReturnRows(productRows())
+	mock.ExpectQuery("SELECT broken").WillReturnError(sqlmock.ErrCancelled)
+	mock.ExpectQuery("SELECT 1").WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(1))
+	mock.ExpectQuery("SELECT 'a;b'").WillReturnRows(sqlmock.NewRows([]string{"s"}).AddRow("a;b"))
+	mock.ExpectQuery("SELECT 3").WillReturnRows(sqlmock.NewRows([]string{"n"}).AddRow(3))
+
+	file := filepath.Join(t.TempDir(), "products.csv")
+	input := strings.Join([]string{
+		`SELECT id, title, city AS "address.city"`,
+		`FROM products;`,
+		`\save ` + file,
+		`\d products`,
+		`\d products; DROP TABLE products`,
+		`\format json`,
+		`\format yaml`,
+		`\timing`,
+		`SELECT broken;`,
+		`SELECT 1;`,
+		`SELECT 'a;b'; SELECT 3;`,
+		`\nope`,
+		`\q`,
+		`SELECT never;`,
+	}, "\n")
+
+	out := runSession(t, dsn, input)
+	for _, want := range []string{
+		"godyno>    ...> +----+-------+--------------+",
+		"|  2 | Lamp  | NULL         |\n+----+-------+--------------+\n(2 rows)\n",
+		"Saved 2 rows to " + file,
+		"address.city  Address.City  string",
+		`error: \d needs a table name`,
+		"Output format is json.",
+		`error: unknown format "yaml"`,
+		"Timing is on.",
+		"error: ",
+		"[\n  {\n    \"n\": 1\n  }\n]\nTime: ",
+		"[\n  {\n    \"s\": \"a;b\"\n  }\n]\nTime: ",
+		"[\n  {\n    \"n\": 3\n  }\n]\nTime: ",
+		`error: unknown command \nope`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Session output does not contain %q:\n%s", want, out)
+		}
+	}
+
+	saved, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("Saved file: %v", err)
+	}
+	if string(saved) != "id,title,address.city\n1,Desk,Izmir\n2,Lamp,\n" {
+		t.Errorf("Saved file = %q", saved)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestREPLDescribeEmpty(t *testing.T) {
+	dsn, mock := setupMock(t)
+	columns := []*sqlmock.Column{
+		sqlmock.NewColumn("id").OfType("INT8", int64(0)).Nullable(false),
+		sqlmock.NewColumn("title").OfType("TEXT", "").Nullable(true),
+	}
+	mock.ExpectQuery("SELECT \\* FROM products LIMIT 100").WillReturnRows(sqlmock.NewRowsWithColumnDefinition(columns...))
+	mock.ExpectQuery("SELECT \\* FROM products LIMIT 100").WillReturnRows(sqlmock.NewRowsWithColumnDefinition(columns...))
+
+	out := runSession(t, dsn, `\d products`)
+	for _, want := range []string{
+		"COLUMN  FIELD  TYPE    DATABASE TYPE  NULLABLE\n",
+		"id      -      int64   INT8           false\n",
+		"title   -      string  TEXT           true\n",
+		"(no rows, types reported by the driver)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Session output does not contain %q:\n%s", want, out)
+		}
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestSplitter(t *testing.T) {
+	testCases := []struct {
+		name  string
+		lines []string
+		want  []string
+	}{
+		{"one line", []string{"SELECT 1; SELECT 2;"}, []string{"SELECT 1", "SELECT 2"}},
+		{"multi line", []string{"SELECT id", "  FROM products;"}, []string{"SELECT id\nFROM products"}},
+		{"quoted", []string{"SELECT 'a;b', \"c;\" FROM t;"}, []string{"SELECT 'a;b', \"c;\" FROM t"}},
+		{"escaped quote", []string{"SELECT 'it''s;';"}, []string{"SELECT 'it''s;'"}},
+		{"literal over lines", []string{"SELECT 'a;", "  b';"}, []string{"SELECT 'a;\n  b'"}},
+		{"comment", []string{"SELECT 1 -- done;", "FROM t;"}, []string{"SELECT 1\nFROM t"}},
+		{"block comment", []string{"SELECT 1 /* ; */;"}, []string{"SELECT 1 /* ; */"}},
+		{"block comment over lines", []string{"SELECT 1 /* a;", "b; */ + 1;"}, []string{"SELECT 1 /* a;\nb; */ + 1"}},
+		{"dollar quoted", []string{"CREATE FUNCTION one() RETURNS int AS $$ BEGIN RETURN 1; END $$ LANGUAGE plpgsql;"},
+			[]string{"CREATE FUNCTION one() RETURNS int AS $$ BEGIN RETURN 1; END $$ LANGUAGE plpgsql"}},
+		{"dollar tag", []string{"DO $body$", "BEGIN", "  PERFORM '$$;';", "END", "$body$;", "SELECT 2;"},
+			[]string{"DO $body$\nBEGIN\n  PERFORM '$$;';\nEND\n$body$", "SELECT 2"}},
+		{"placeholders", []string{"SELECT $1, a$b FROM t WHERE x = $2; SELECT 3;"}, []string{"SELECT $1, a$b FROM t WHERE x = $2", "SELECT 3"}},
+		{"unfinished", []string{"SELECT 1; SELECT"}, []string{"SELECT 1"}},
+		{"empty statements", []string{";;", "  ;"}, nil},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var sp splitter
+			var got []string
+			for _, line := range tc.lines {
+				got = append(got, sp.feed(line)...)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("feed() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,199 @@
+package godyno
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	gotoken "go/token"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// GenerateStruct - returns gofmt-ed Go source of package pkg declaring a struct
+// named name with the shape of dr. Nested fields become named types (e.g.
+// ProductAddress), field names follow Go style (created_at becomes CreatedAt,
+// id becomes ID), nullable columns become pointers and every field keeps its
+// column name in json and db tags. A Scan<name> function scanning a row in query column order
+// (see Metadata) is generated as well.
+func GenerateStruct(pkg, name string, dr *DBResult) ([]byte, error) {
+	if !gotoken.IsIdentifier(pkg) {
+		return nil, fmt.Errorf("invalid package name %q", pkg)
+	}
+	if !isIdentifier(name) {
+		return nil, fmt.Errorf("invalid type name %q: must be an exported identifier", name)
+	}
+	if dr.Type() == nil {
+		return nil, fmt.Errorf("cannot generate %s from an empty result", name)
+	}
+
+	g := &generator{dr: dr, imports: map[string]bool{}}
+	g.structType(name, dr.typ, nil, nil)
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "package %s\n\n", pkg)
+	if len(g.imports) > 0 {
+		imports := make([]string, 0, len(g.imports))
+		for imp := range g.imports {
+			imports = append(imports, fmt.Sprintf("%q", imp))
+		}
+		sort.Strings(imports)
+		fmt.Fprintf(&src, "import (\n%s\n)\n\n", strings.Join(imports, "\n"))
+	}
+	src.WriteString(g.decls.String())
+	g.scanFunc(&src, name)
+
+	out, err := format.Source(src.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated source: %w", err)
+	}
+	return out, nil
+}
+
+// generator - collects the type declarations and imports of the generated source
+type generator struct {
+	dr      *DBResult
+	decls   bytes.Buffer
+	imports map[string]bool
+	fields  map[string]string // column -> Go selector, e.g. "address.city" -> "Address.City"
+}
+
+// structType - declares a named struct for one level of the dynamic type, nested levels first in order.
+// goPath holds the Go field names leading to this level.
+func (g *generator) structType(name string, t reflect.Type, path, goPath []string) {
+	var body strings.Builder
+	var nested []func()
+	used := map[string]bool{}
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		key := fieldKey(sf)
+		fieldPath := append(append([]string{}, path...), key)
+
+		fieldName := goName(key)
+		if fieldName == "" {
+			fieldName = sf.Name
+		}
+		base := fieldName
+		for n := 2; used[fieldName]; n++ {
+			fieldName = fmt.Sprintf("%s%d", base, n)
+		}
+		used[fieldName] = true
+		fieldGoPath := append(append([]string{}, goPath...), fieldName)
+
+		typeName := ""
+		if isNested(sf.Type) {
+			typeName = name + fieldName
+			nestedType := sf.Type
+			nested = append(nested, func() { g.structType(typeName, nestedType, fieldPath, fieldGoPath) })
+		} else {
+			typeName = g.typeName(sf.Type)
+			column := strings.Join(fieldPath, ".")
+			if g.dr.nullable(column) && sf.Type.Kind() != reflect.Slice {
+				typeName = "*" + typeName
+			}
+			if g.fields == nil {
+				g.fields = map[string]string{}
+			}
+			g.fields[column] = strings.Join(fieldGoPath, ".")
+		}
+
+		fmt.Fprintf(&body, "\t%s %s `json:\"%s\" db:\"%s\"`\n", fieldName, typeName, key, key)
+	}
+
+	if path == nil {
+		fmt.Fprintf(&g.decls, "// %s - generated from the shape of a query result\n", name)
+	} else {
+		fmt.Fprintf(&g.decls, "// %s - the %s fields of a query result\n", name, strings.Join(path, "."))
+	}
+	fmt.Fprintf(&g.decls, "type %s struct {\n%s}\n\n", name, body.String())
+
+	for _, declare := range nested {
+		declare()
+	}
+}
+
+// commonInitialisms - words written in upper case in Go names, as golint suggests
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "CSV": true,
+	"DNS": true, "EOF": true, "GUID": true, "HTML": true, "HTTP": true, "HTTPS": true,
+	"ID": true, "IP": true, "JSON": true, "SKU": true, "SQL": true, "SSH": true,
+	"TCP": true, "TLS": true, "TTL": true, "UDP": true, "UI": true, "UID": true,
+	"URI": true, "URL": true, "UTF8": true, "UUID": true, "XML": true,
+}
+
+// goName - returns the Go style name of a column key, e.g. "created_at" -> "CreatedAt"
+// and "user_id" -> "UserID"; "" when the result is not a valid exported identifier
+func goName(key string) string {
+	var b strings.Builder
+	for _, word := range strings.FieldsFunc(key, func(r rune) bool { return r == '_' || r == '-' || r == ' ' }) {
+		if upper := strings.ToUpper(word); commonInitialisms[upper] {
+			b.WriteString(upper)
+			continue
+		}
+		b.WriteString(toTitle(word))
+	}
+
+	if name := b.String(); isIdentifier(name) {
+		return name
+	}
+	return ""
+}
+
+// typeName - returns the Go expression of a field type and records its imports
+func (g *generator) typeName(t reflect.Type) string {
+	if t == bytesType {
+		return "[]byte"
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		return "*" + g.typeName(t.Elem())
+	case reflect.Slice:
+		return "[]" + g.typeName(t.Elem())
+	case reflect.Array:
+		return fmt.Sprintf("[%d]%s", t.Len(), g.typeName(t.Elem()))
+	case reflect.Map:
+		return "map[" + g.typeName(t.Key()) + "]" + g.typeName(t.Elem())
+	}
+
+	if t.PkgPath() != "" {
+		g.imports[t.PkgPath()] = true
+	}
+	return t.String()
+}
+
+// scanFunc - writes a function scanning a row into the generated type
+func (g *generator) scanFunc(src *bytes.Buffer, name string) {
+	// prefer the query order; Set and Unset may have changed the fields since
+	var columns []string
+	if g.dr.meta != nil && len(g.dr.meta.Columns) == len(g.fields) {
+		for _, col := range g.dr.meta.Columns {
+			if _, ok := g.fields[col.Name]; !ok {
+				columns = nil
+				break
+			}
+			columns = append(columns, col.Name)
+		}
+	}
+	if columns == nil {
+		for _, f := range g.dr.Fields() {
+			columns = append(columns, f.Column)
+		}
+	}
+
+	targets := make([]string, len(columns))
+	for i, column := range columns {
+		targets[i] = "&v." + g.fields[column]
+	}
+
+	fmt.Fprintf(src, "// Scan%s - scans a row (e.g. *sql.Rows or *sql.Row) into a %s.\n", name, name)
+	fmt.Fprintf(src, "// The query must select the columns in this order: %s\n", strings.Join(columns, ", "))
+	fmt.Fprintf(src, "func Scan%s(row interface{ Scan(dest ...any) error }) (%s, error) {\n", name, name)
+	fmt.Fprintf(src, "var v %s\n", name)
+	fmt.Fprintf(src, "err := row.Scan(%s)\n", strings.Join(targets, ", "))
+	src.WriteString("return v, err\n}\n")
+}
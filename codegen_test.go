@@ -0,0 +1,107 @@
+package godyno
+
+import (
+	"errors"
+	"go/parser"
+	gotoken "go/token"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestGenerateStruct(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	created := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT").WillReturnRows(
+		sqlmock.NewRows([]string{"address.city", "id", "title", "created_at", "address.geo.lat"}).
+			AddRow("Izmir", 1, "Desk", created, 38.4).
+			AddRow(nil, 2, "Lamp", created, 39.9),
+	)
+	results, err := QueryToStruct(db, "SELECT * FROM products", WithSampleRows(0))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	src, err := GenerateStruct("models", "Product", results[0])
+	if err != nil {
+		t.Fatalf("GenerateStruct() error = %v", err)
+	}
+
+	code := string(src)
+	for _, part := range []string{
+		"package models\n",
+		"import (\n\t\"time\"\n)\n",
+		"type Product struct {\n\tAddress   ProductAddress `json:\"address\" db:\"address\"`\n\tID        int64          `json:\"id\" db:\"id\"`\n",
+		"\tCreatedAt time.Time      `json:\"created_at\" db:\"created_at\"`\n",
+		"type ProductAddress struct {\n\tCity *string           `json:\"city\" db:\"city\"`\n\tGeo  ProductAddressGeo `json:\"geo\" db:\"geo\"`\n}",
+		"type ProductAddressGeo struct {\n\tLat float64 `json:\"lat\" db:\"lat\"`\n}",
+		"// The query must select the columns in this order: address.city, id, title, created_at, address.geo.lat\n",
+		"func ScanProduct(row interface{ Scan(dest ...any) error }) (Product, error) {\n\tvar v Product\n" +
+			"\terr := row.Scan(&v.Address.City, &v.ID, &v.Title, &v.CreatedAt, &v.Address.Geo.Lat)\n",
+	} {
+		if !strings.Contains(code, part) {
+			t.Errorf("Generated source does not contain:\n%s\n\nsource:\n%s", part, code)
+		}
+	}
+
+	if _, err := parser.ParseFile(gotoken.NewFileSet(), "product.go", src, 0); err != nil {
+		t.Errorf("Generated source does not parse: %v", err)
+	}
+
+	t.Run("invalid names", func(t *testing.T) {
+		for _, tt := range []struct{ pkg, name string }{{"models", "product"}, {"my-models", "Product"}} {
+			if _, err := GenerateStruct(tt.pkg, tt.name, results[0]); err == nil {
+				t.Errorf("GenerateStruct(%q, %q) should fail", tt.pkg, tt.name)
+			}
+		}
+		if _, err := GenerateStruct("models", "Product", nil); err == nil || errors.Is(err, ErrReflection) {
+			t.Errorf("GenerateStruct(nil) error = %v", err)
+		}
+	})
+}
+
+func TestGoName(t *testing.T) {
+	testCases := map[string]string{
+		"id":           "ID",
+		"created_at":   "CreatedAt",
+		"user_id":      "UserID",
+		"api_url":      "APIURL",
+		"title":        "Title",
+		"createdAt":    "CreatedAt",
+		"c2024_01":     "C202401",
+		"_":            "",
+		"2024":         "",
+		"ürün_adı":     "ÜrünAdı",
+		"Content-Type": "ContentType",
+	}
+
+	for input, want := range testCases {
+		if got := goName(input); got != want {
+			t.Errorf("goName(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestGenerateStructNameClash(t *testing.T) {
+	dr, err := FromMap(map[string]any{"created_at": "a", "createdAt": "b"})
+	if err != nil {
+		t.Fatalf("FromMap() error = %v", err)
+	}
+
+	src, err := GenerateStruct("models", "Row", dr)
+	if err != nil {
+		t.Fatalf("GenerateStruct() error = %v", err)
+	}
+	for _, part := range []string{
+		"CreatedAt  string `json:\"createdAt\" db:\"createdAt\"`",
+		"CreatedAt2 string `json:\"created_at\" db:\"created_at\"`",
+	} {
+		if !strings.Contains(string(src), part) {
+			t.Errorf("Generated source does not contain %s:\n%s", part, src)
+		}
+	}
+}
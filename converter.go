@@ -0,0 +1,98 @@
+package godyno
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strings"
+)
+
+// Converter - converts a raw driver value into a value of Type.
+// Convert is never called for NULL values; those keep the zero value.
+type Converter struct {
+	Type    reflect.Type
+	Convert func(src any) (any, error)
+}
+
+// NewConverter - creates a Converter whose type is taken from the function's result
+func NewConverter[T any](fn func(src any) (T, error)) Converter {
+	return Converter{
+		Type: reflect.TypeFor[T](),
+		Convert: func(src any) (any, error) {
+			return fn(src)
+		},
+	}
+}
+
+// ConverterRegistry - holds converters keyed by database type name or column name pattern
+type ConverterRegistry struct {
+	dbTypes map[string]Converter
+	columns []columnConverter
+}
+
+// columnConverter - a converter registered for a column name pattern
+type columnConverter struct {
+	pattern string
+	conv    Converter
+}
+
+// NewConverterRegistry - returns an empty registry
+func NewConverterRegistry() *ConverterRegistry {
+	return &ConverterRegistry{
+		dbTypes: make(map[string]Converter),
+	}
+}
+
+// RegisterType - registers a converter for a database type name (e.g. "citext").
+// Type names are matched case-insensitively.
+func (r *ConverterRegistry) RegisterType(dbType string, conv Converter) error {
+	if err := conv.validate(); err != nil {
+		return err
+	}
+	r.dbTypes[strings.ToUpper(dbType)] = conv
+	return nil
+}
+
+// RegisterColumn - registers a converter for column names matching pattern.
+// The pattern uses path.Match syntax, e.g. "status" or "*_cents".
+// Column patterns take precedence over database types and are tried in
+// registration order.
+func (r *ConverterRegistry) RegisterColumn(pattern string, conv Converter) error {
+	if err := conv.validate(); err != nil {
+		return err
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("invalid column pattern %q: %w", pattern, err)
+	}
+	r.columns = append(r.columns, columnConverter{pattern: pattern, conv: conv})
+	return nil
+}
+
+// lookup - finds the converter for a column, if any
+func (r *ConverterRegistry) lookup(column, dbType string) (Converter, bool) {
+	if r == nil {
+		return Converter{}, false
+	}
+
+	for _, cc := range r.columns {
+		if ok, _ := path.Match(cc.pattern, column); ok {
+			return cc.conv, true
+		}
+	}
+
+	if dbType != "" {
+		if conv, ok := r.dbTypes[strings.ToUpper(dbType)]; ok {
+			return conv, true
+		}
+	}
+
+	return Converter{}, false
+}
+
+// validate - checks that the converter can be used
+func (c Converter) validate() error {
+	if c.Type == nil || c.Convert == nil {
+		return fmt.Errorf("converter needs both Type and Convert")
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package godyno
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// Money - amount in cents, used to test column converters
+type Money int64
+
+// Status - enum type, used to test column converters
+type Status string
+
+func TestConverterRegistry(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	registry := NewConverterRegistry()
+	if err := registry.RegisterType("citext", NewConverter(func(src any) (string, error) {
+		return fmt.Sprintf("%s", src), nil
+	})); err != nil {
+		t.Fatalf("RegisterType() error = %v", err)
+	}
+	if err := registry.RegisterColumn("status", NewConverter(func(src any) (Status, error) {
+		return Status(fmt.Sprintf("%s", src)), nil
+	})); err != nil {
+		t.Fatalf("RegisterColumn() error = %v", err)
+	}
+	if err := registry.RegisterColumn("*_cents", NewConverter(func(src any) (Money, error) {
+		n, err := strconv.ParseInt(fmt.Sprintf("%s", src), 10, 64)
+		return Money(n), err
+	})); err != nil {
+		t.Fatalf("RegisterColumn() error = %v", err)
+	}
+
+	t.Run("Type and column converters", func(t *testing.T) {
+		rows := sqlmock.NewRowsWithColumnDefinition(
+			sqlmock.NewColumn("code").OfType("CITEXT", ""),
+			sqlmock.NewColumn("status").OfType("TEXT", ""),
+			sqlmock.NewColumn("price_cents").OfType("TEXT", ""),
+		).AddRow([]byte("123"), []byte("active"), []byte("1999"))
+		mock.ExpectQuery("SELECT").WillReturnRows(rows)
+
+		results, err := QueryToStruct(db, "SELECT code, status, price_cents FROM products", WithConverters(registry))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		// citext must stay a string even though it looks like a number
+		if got, ok := results[0].Get("code").(string); !ok || got != "123" {
+			t.Errorf("Get(code) = %#v, want string 123", results[0].Get("code"))
+		}
+		if got, ok := results[0].Get("status").(Status); !ok || got != "active" {
+			t.Errorf("Get(status) = %#v, want Status active", results[0].Get("status"))
+		}
+		if got, ok := results[0].Get("price_cents").(Money); !ok || got != 1999 {
+			t.Errorf("Get(price_cents) = %#v, want Money 1999", results[0].Get("price_cents"))
+		}
+	})
+
+	t.Run("Converter error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT").WillReturnRows(
+			sqlmock.NewRows([]string{"total_cents"}).AddRow([]byte("abc")),
+		)
+
+		if _, err := QueryToStruct(db, "SELECT total_cents FROM orders", WithConverters(registry)); err == nil {
+			t.Error("Expected a converter error but got none")
+		}
+	})
+
+	t.Run("Options are not sent as arguments", func(t *testing.T) {
+		mock.ExpectQuery("SELECT").WithArgs(5).WillReturnRows(
+			sqlmock.NewRows([]string{"id"}).AddRow(5),
+		)
+
+		results, err := QueryToStruct(db, "SELECT id FROM products WHERE id = $1", 5, WithConverters(registry))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if results[0].GetInt("id") != 5 {
+			t.Errorf("GetInt(id) = %d, want 5", results[0].GetInt("id"))
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestConverterRegistryValidation(t *testing.T) {
+	registry := NewConverterRegistry()
+
+	if err := registry.RegisterType("citext", Converter{}); err == nil {
+		t.Error("Expected an error for an empty converter")
+	}
+	conv := NewConverter(func(src any) (string, error) { return "", nil })
+	if err := registry.RegisterColumn("[", conv); err == nil {
+		t.Error("Expected an error for a malformed pattern")
+	}
+}
@@ -0,0 +1,196 @@
+package godyno
+
+import (
+	"encoding/base64"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// CSVWriter - writes results as CSV. The header is taken from the columns of
+// the first row, nested fields appear as dotted headers (e.g. "address.city").
+// Set the exported fields before the first Write.
+type CSVWriter struct {
+	// Comma is the field delimiter, ',' by default
+	Comma rune
+	// Null is written for NULL values, "" by default
+	Null string
+	// TimeFormat is the layout for time.Time values, time.RFC3339 by default
+	TimeFormat string
+	// NoHeader skips the header line
+	NoHeader bool
+
+	out     io.Writer
+	w       *csv.Writer
+	columns []string
+}
+
+// NewCSVWriter - returns a CSVWriter writing to w
+func NewCSVWriter(w io.Writer) *CSVWriter {
+	return &CSVWriter{Comma: ',', TimeFormat: time.RFC3339, out: w}
+}
+
+// Write - writes a row, preceded by the header on the first call.
+// Rows are buffered; call Flush when done.
+func (cw *CSVWriter) Write(dr *DBResult) error {
+	if cw.w == nil {
+		cw.w = csv.NewWriter(cw.out)
+		cw.w.Comma = cw.Comma
+
+		for _, f := range dr.Fields() {
+			cw.columns = append(cw.columns, f.Column)
+		}
+		if !cw.NoHeader {
+			if err := cw.w.Write(cw.columns); err != nil {
+				return err
+			}
+		}
+	}
+
+	record := make([]string, len(cw.columns))
+	for i, column := range cw.columns {
+		record[i] = cw.format(dr, column)
+	}
+	return cw.w.Write(record)
+}
+
+// WriteAll - writes every row and flushes
+func (cw *CSVWriter) WriteAll(rs ResultSet) error {
+	for _, dr := range rs {
+		if err := cw.Write(dr); err != nil {
+			return err
+		}
+	}
+	return cw.Flush()
+}
+
+// Flush - writes any buffered rows to the underlying writer
+func (cw *CSVWriter) Flush() error {
+	if cw.w == nil {
+		return nil
+	}
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// format - returns the CSV text of a field
+func (cw *CSVWriter) format(dr *DBResult, column string) string {
+	if dr.IsNull(column) || !dr.Has(column) {
+		return cw.Null
+	}
+	return formatValue(dr.Get(column), cw.TimeFormat)
+}
+
+// formatValue - returns the text form of a field value used by the text exporters.
+// Times use layout, byte slices are base64 encoded like in JSON.
+func formatValue(val any, layout string) string {
+	switch v := val.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		if layout == "" {
+			layout = time.RFC3339
+		}
+		return v.Format(layout)
+	case []byte:
+		return base64.StdEncoding.EncodeToString(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	}
+	return fmt.Sprint(val)
+}
+
+// CSVReader - reads CSV into results using the same type inference as QueryToStruct.
+// The first line is the header; dotted headers become nested fields.
+type CSVReader struct {
+	// Comma is the field delimiter, ',' by default
+	Comma rune
+	// Null is the cell text read as NULL, "" by default
+	Null string
+
+	in io.Reader
+}
+
+// NewCSVReader - returns a CSVReader reading from r
+func NewCSVReader(r io.Reader) *CSVReader {
+	return &CSVReader{Comma: ',', in: r}
+}
+
+// ReadAll - reads every row. Inference options such as WithSampleRows,
+// WithStrictStrings or WithConverters (matched by column name) apply.
+func (cr *CSVReader) ReadAll(opts ...Option) (results ResultSet, err error) {
+	defer recoverError(&err, ErrReflection, "")
+
+	cfg, _ := newConfig(optionArgs(opts))
+
+	r := csv.NewReader(cr.in)
+	r.Comma = cr.Comma
+
+	header, err := r.Read()
+	if errors.Is(err, io.EOF) {
+		return ResultSet{}, nil
+	}
+	if err != nil {
+		return nil, newError(ErrScan, "", err)
+	}
+
+	cols, err := newColumns(header, nil, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	err = buildResults(&csvRows{r: r, null: cr.Null}, cols, cfg, func(result *DBResult) error {
+		results = append(results, result)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if results == nil {
+		results = ResultSet{}
+	}
+	return results, nil
+}
+
+// csvRows - reads raw row values from a csv.Reader, as []byte like database drivers do
+type csvRows struct {
+	r      *csv.Reader
+	null   string
+	record []string
+	err    error
+}
+
+func (c *csvRows) Next() bool {
+	c.record, c.err = c.r.Read()
+	if errors.Is(c.err, io.EOF) {
+		c.err = nil
+		return false
+	}
+	return true
+}
+
+func (c *csvRows) Scan() ([]any, error) {
+	if c.err != nil {
+		return nil, newError(ErrScan, "", c.err)
+	}
+
+	values := make([]any, len(c.record))
+	for i, cell := range c.record {
+		if cell != c.null {
+			values[i] = []byte(cell)
+		}
+	}
+	return values, nil
+}
+
+func (c *csvRows) Err() error {
+	return nil
+}
@@ -0,0 +1,110 @@
+package godyno
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestCSVWriter(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	created := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "title", "price", "created_at", "address.city"}).
+			AddRow(1, "Desk; oak", 199.5, created, "Izmir").
+			AddRow(2, "Lamp", 19.9, created, nil),
+	)
+	results, err := QueryToStruct(db, "SELECT * FROM products")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var buf strings.Builder
+	w := NewCSVWriter(&buf)
+	w.Comma = ';'
+	w.Null = "NULL"
+	w.TimeFormat = "2006-01-02"
+	if err := w.WriteAll(results); err != nil {
+		t.Fatalf("WriteAll() error = %v", err)
+	}
+
+	want := "id;title;price;created_at;address.city\n" +
+		"1;\"Desk; oak\";199.5;2024-01-15;Izmir\n" +
+		"2;Lamp;19.9;2024-01-15;NULL\n"
+	if buf.String() != want {
+		t.Errorf("WriteAll() =\n%s\nwant\n%s", buf.String(), want)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCSVReader(t *testing.T) {
+	input := "id,title,price,active,zip,address.city\n" +
+		"1,Desk,199.5,true,01234,Izmir\n" +
+		"2,Lamp,19,false,35000,\n"
+
+	results, err := NewCSVReader(strings.NewReader(input)).ReadAll(WithSampleRows(0), WithPreserveLeadingZeros())
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+	if len(results) != 2 {
+		t.Fatalf("ReadAll() returned %d rows, want 2", len(results))
+	}
+
+	first, second := results[0], results[1]
+	if first.Get("id") != 1 || first.Get("price") != 199.5 || second.Get("price") != 19.0 {
+		t.Errorf("Unexpected numbers: %v, %v", first.ToMap(), second.ToMap())
+	}
+	if first.Get("active") != true || first.Get("zip") != "01234" {
+		t.Errorf("Unexpected values: %v", first.ToMap())
+	}
+	if first.GetString("address.city") != "Izmir" || !second.IsNull("address.city") {
+		t.Errorf("Unexpected nested values: %v, %v", first.ToMap(), second.ToMap())
+	}
+}
+
+func TestCSVRoundTrip(t *testing.T) {
+	input := "id;name\n1;Ayse\n2;Mehmet\n"
+
+	r := NewCSVReader(strings.NewReader(input))
+	r.Comma = ';'
+	results, err := r.ReadAll()
+	if err != nil {
+		t.Fatalf("ReadAll() error = %v", err)
+	}
+
+	var buf strings.Builder
+	w := NewCSVWriter(&buf)
+	w.Comma = ';'
+	if err := w.WriteAll(results); err != nil {
+		t.Fatalf("WriteAll() error = %v", err)
+	}
+	if buf.String() != input {
+		t.Errorf("Round trip = %q, want %q", buf.String(), input)
+	}
+}
+
+func TestCSVReaderErrors(t *testing.T) {
+	_, err := NewCSVReader(strings.NewReader("id,name\n1,a\n2\n")).ReadAll()
+	var e *Error
+	if !errors.As(err, &e) || !errors.Is(err, ErrScan) || e.Row != 1 {
+		t.Errorf("ReadAll() error = %v, want ErrScan at row 1", err)
+	}
+
+	_, err = NewCSVReader(strings.NewReader("id,first name\n")).ReadAll()
+	if !errors.Is(err, ErrInvalidColumnName) {
+		t.Errorf("ReadAll() error = %v, want ErrInvalidColumnName", err)
+	}
+
+	results, err := NewCSVReader(strings.NewReader("")).ReadAll()
+	if err != nil || len(results) != 0 {
+		t.Errorf("ReadAll() of empty input = %v, %v", results, err)
+	}
+}
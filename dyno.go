@@ -1,6 +1,7 @@
 package godyno
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"reflect"
@@ -14,8 +15,11 @@ import (
 type DBResult struct {
 	value any
 	typ   reflect.Type
+	meta  *Metadata
+	raw   map[string]any
 }
 
+// New - returns an empty result; fields can be added with Set, see also FromMap
 func New() *DBResult {
 	return &DBResult{}
 }
@@ -35,273 +39,440 @@ func toTitle(s string) string {
 	return string(r)
 }
 
-// QueryToStruct - converts database query results to dynamic struct
-func QueryToStruct(db *sql.DB, query string, args ...any) ([]*DBResult, error) {
-	rows, err := db.Query(query, args...)
+// QueryToStruct - converts database query results to dynamic struct.
+// Options such as WithConverters or WithSampleRows may be passed among args;
+// they only apply to this call and are not sent to the database.
+func QueryToStruct(db *sql.DB, query string, args ...any) (ResultSet, error) {
+	return QueryToStructContext(context.Background(), db, query, args...)
+}
+
+// QueryToStructContext - like QueryToStruct; the query is cancelled with ctx
+func QueryToStructContext(ctx context.Context, db *sql.DB, query string, args ...any) (ResultSet, error) {
+	var results ResultSet
+	err := runQuery(ctx, db, query, args, func(result *DBResult) error {
+		results = append(results, result)
+		return nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("sorgu hatası: %w", err)
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// QueryEach - streams the rows of a query to fn without holding the result in memory.
+// Only the rows sampled for type inference (see WithSampleRows) are
+// buffered. An error returned by fn stops the iteration and is returned; a
+// panic in fn is not recovered.
+func QueryEach(db *sql.DB, query string, fn func(*DBResult) error, args ...any) error {
+	return runQuery(context.Background(), db, query, args, fn)
+}
+
+// QueryEachContext - like QueryEach; the query is cancelled with ctx
+func QueryEachContext(ctx context.Context, db *sql.DB, query string, fn func(*DBResult) error, args ...any) error {
+	return runQuery(ctx, db, query, args, fn)
+}
+
+// runQuery - runs the query and passes every converted row to emit.
+// Panics are recovered only around the conversion, so a panic in emit reaches the caller.
+func runQuery(ctx context.Context, db *sql.DB, query string, args []any, emit func(*DBResult) error) error {
+	cfg, args := newConfig(args)
+
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return newError(ErrQuery, "", err)
 	}
 	defer rows.Close()
 
 	// Get column names and types
 	columns, err := rows.Columns()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get column names: %w", err)
+		return newError(ErrQuery, "", fmt.Errorf("failed to get column names: %w", err))
+	}
+	columnTypes, err := rows.ColumnTypes()
+	if err != nil {
+		return newError(ErrQuery, "", fmt.Errorf("failed to get column types: %w", err))
 	}
 
-	// Read first row and determine field types
-	var results []*DBResult
-	fieldTypes := make(map[string]reflect.Type)
-	fieldMap := make(map[string][]FieldInfo)
-
-	// Analyze column names and determine nested structures
-	for _, col := range columns {
-		parts := strings.Split(col, ".")
-		if len(parts) > 1 {
-			// Nested field (e.g.: address.city)
-			parent := parts[0]
-			child := parts[1]
+	cols, err := newColumns(columns, columnTypes, cfg)
+	if err != nil {
+		return err
+	}
 
-			if _, exists := fieldMap[parent]; !exists {
-				fieldMap[parent] = []FieldInfo{}
-			}
+	return buildResults(sqlRows{rows: rows, n: len(columns)}, cols, cfg, emit)
+}
 
-			// Initially assume string type, can be changed later
-			fieldMap[parent] = append(fieldMap[parent], FieldInfo{
-				Name: child,
-				Type: reflect.TypeOf(""),
-			})
-		} else {
-			// Flat field, initially assumed to be string
-			fieldTypes[col] = reflect.TypeOf("")
+// newColumns - analyzes column names, nested structures and registered converters.
+// columnTypes may be shorter than names (or nil) when the source has no type information.
+func newColumns(names []string, columnTypes []*sql.ColumnType, cfg *config) ([]column, error) {
+	cols := make([]column, len(names))
+	for i, name := range names {
+		cols[i] = column{
+			name: name,
+			path: strings.Split(name, "."),
+			typ:  stringType,
+		}
+		if i < len(columnTypes) && columnTypes[i] != nil {
+			cols[i].dbType = columnTypes[i].DatabaseTypeName()
+			cols[i].nullable, _ = columnTypes[i].Nullable()
 		}
+		if conv, ok := cfg.converters.lookup(name, cols[i].dbType); ok {
+			cols[i].conv = &conv
+			cols[i].typ = conv.Type
+		}
+	}
+
+	if err := validateColumns(cols); err != nil {
+		return nil, err
+	}
+	return cols, nil
+}
+
+// rowSource - supplies raw row values to buildResults
+type rowSource interface {
+	Next() bool
+	Scan() ([]any, error)
+	Err() error
+}
+
+// sqlRows - reads raw row values from *sql.Rows
+type sqlRows struct {
+	rows *sql.Rows
+	n    int
+}
+
+func (r sqlRows) Next() bool {
+	return r.rows.Next()
+}
+
+func (r sqlRows) Scan() ([]any, error) {
+	// Slice to hold values
+	values := make([]any, r.n)
+	valuePtrs := make([]any, r.n)
+
+	for i := range values {
+		valuePtrs[i] = &values[i]
+	}
+
+	if err := r.rows.Scan(valuePtrs...); err != nil {
+		return nil, newError(ErrScan, "", err)
 	}
+	return values, nil
+}
+
+func (r sqlRows) Err() error {
+	if err := r.rows.Err(); err != nil {
+		return newError(ErrRowIteration, "", err)
+	}
+	return nil
+}
 
-	// Determine field types for the first row
-	if rows.Next() {
-		// Slice to hold values
-		values := make([]any, len(columns))
-		valuePtrs := make([]any, len(columns))
+// buildResults - determines field types from the sampled rows and passes every row to emit.
+// Rows are buffered only until the sample is complete.
+func buildResults(src rowSource, cols []column, cfg *config, emit func(*DBResult) error) error {
+	var sample [][]any
+	var structType reflect.Type
+	var meta *Metadata
+	row := 0
 
-		for i := range columns {
-			valuePtrs[i] = &values[i]
+	fill := func(values []any, row int) error {
+		result, err := fillStruct(structType, cols, values, cfg)
+		if err != nil {
+			return atRow(err, row)
 		}
+		result.meta = meta
+		return emit(result)
+	}
 
-		if err := rows.Scan(valuePtrs...); err != nil {
-			return nil, fmt.Errorf("satır taranamadı: %w", err)
+	// infer - types the columns from the sample and flushes the buffered rows
+	infer := func() error {
+		if err := func() (err error) {
+			defer recoverError(&err, ErrReflection, "")
+			meta = inferColumns(cols, sample, cfg)
+			structType, err = buildStructType(cols)
+			return err
+		}(); err != nil {
+			return err
 		}
 
-		// Update type information
-		for i, col := range columns {
-			val := values[i]
-			parts := strings.Split(col, ".")
-
-			// Determine type
-			var valueType reflect.Type
-			switch v := val.(type) {
-			case []byte:
-				// Byte array, possible types: string, int, float, bool
-				str := string(v)
-
-				// Is it a number?
-				if _, err := strconv.Atoi(str); err == nil {
-					valueType = reflect.TypeOf(int(0))
-				} else if _, err := strconv.ParseFloat(str, 64); err == nil {
-					valueType = reflect.TypeOf(float64(0))
-				} else if _, err := strconv.ParseBool(str); err == nil {
-					valueType = reflect.TypeOf(bool(false))
-				} else {
-					valueType = reflect.TypeOf("")
-				}
-			case nil:
-				valueType = reflect.TypeOf("")
-			default:
-				valueType = reflect.TypeOf(val)
+		for i, values := range sample {
+			// The sample always starts at the first row
+			if err := fill(values, i); err != nil {
+				return err
 			}
+		}
+		sample = nil
+		return nil
+	}
 
-			if len(parts) > 1 {
-				// Update type for nested field
-				parent := parts[0]
-				child := parts[1]
+	for ; src.Next(); row++ {
+		values, err := src.Scan()
+		if err != nil {
+			return atRow(err, row)
+		}
 
-				for i, field := range fieldMap[parent] {
-					if field.Name == child {
-						fieldMap[parent][i].Type = valueType
-						break
-					}
-				}
-			} else {
-				// Update type for flat field
-				fieldTypes[col] = valueType
+		if structType != nil {
+			if err := fill(values, row); err != nil {
+				return err
 			}
+			continue
 		}
 
-		// Create struct for the first row
-		result, err := createStruct(columns, values, fieldTypes, fieldMap)
-		if err != nil {
-			return nil, err
+		sample = append(sample, values)
+		if cfg.sampleRows > 0 && len(sample) >= cfg.sampleRows {
+			if err := infer(); err != nil {
+				return err
+			}
 		}
-		results = append(results, result)
 	}
 
-	// Process remaining rows
-	for rows.Next() {
-		values := make([]any, len(columns))
-		valuePtrs := make([]any, len(columns))
+	if err := src.Err(); err != nil {
+		return err
+	}
 
-		for i := range columns {
-			valuePtrs[i] = &values[i]
-		}
+	// Fewer rows than the sample size, or the whole result was sampled
+	if structType == nil && len(sample) > 0 {
+		return infer()
+	}
+	return nil
+}
 
-		if err := rows.Scan(valuePtrs...); err != nil {
-			return nil, fmt.Errorf("satır taranamadı: %w", err)
-		}
+// column - describes a result column and the Go type chosen for it
+type column struct {
+	name     string
+	path     []string
+	dbType   string
+	nullable bool
+	typ      reflect.Type
+	conv     *Converter
+}
+
+// binaryTypes - database type names whose values are kept as []byte
+var binaryTypes = map[string]bool{
+	"BYTEA":      true,
+	"BLOB":       true,
+	"TINYBLOB":   true,
+	"MEDIUMBLOB": true,
+	"LONGBLOB":   true,
+	"BINARY":     true,
+	"VARBINARY":  true,
+	"IMAGE":      true,
+	"RAW":        true,
+}
+
+// isBinary - reports whether the column holds binary data
+func (c column) isBinary() bool {
+	return binaryTypes[strings.ToUpper(c.dbType)]
+}
 
-		result, err := createStruct(columns, values, fieldTypes, fieldMap)
+// convert - converts a raw driver value to the column's type
+func (c column) convert(val any, cfg *config) (any, error) {
+	if val == nil {
+		return nil, nil
+	}
+
+	if c.conv != nil {
+		out, err := c.conv.Convert(val)
 		if err != nil {
-			return nil, err
+			return nil, newError(ErrTypeMismatch, c.name, err)
 		}
-		results = append(results, result)
+		if out != nil && !reflect.TypeOf(out).AssignableTo(c.typ) {
+			return nil, newError(ErrTypeMismatch, c.name, fmt.Errorf("converter returned %T, want %s", out, c.typ))
+		}
+		return out, nil
 	}
 
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("satır işleme hatası: %w", err)
+	// Convert byte array to the correct type
+	if byteArray, ok := val.([]byte); ok {
+		str := string(byteArray)
+
+		switch {
+		case c.typ.Kind() == reflect.String:
+			return str, nil
+		case c.typ.Kind() == reflect.Slice:
+			return byteArray, nil
+		case c.typ.Kind() == reflect.Bool:
+			if b, ok := cfg.parseBool(str); ok {
+				return b, nil
+			}
+		case isFloat(c.typ):
+			if num, ok := cfg.parseFloat(str); ok {
+				return coerce(num, c.typ)
+			}
+		case isNumeric(c.typ):
+			if num, ok := cfg.parseInt(str); ok {
+				return coerce(num, c.typ)
+			}
+		}
+		return nil, newError(ErrTypeMismatch, c.name, fmt.Errorf("cannot convert %q to %s", str, c.typ))
 	}
 
-	return results, nil
+	out, err := coerce(val, c.typ)
+	if err != nil {
+		return nil, newError(ErrTypeMismatch, c.name, err)
+	}
+	return out, nil
 }
 
-// createStruct - creates a dynamic struct with field types and values
-func createStruct(columns []string, values []any, fieldTypes map[string]reflect.Type, fieldMap map[string][]FieldInfo) (*DBResult, error) {
-	// Create struct types for the parent fields
-	structFields := []reflect.StructField{}
-	parentStructs := make(map[string]reflect.Type)
-
-	// First create struct types for nested fields
-	for parent, fields := range fieldMap {
-		nestedFields := []reflect.StructField{}
-
-		for _, field := range fields {
-			nestedFields = append(nestedFields, reflect.StructField{
-				Name: toTitle(field.Name),
-				Type: field.Type,
-				Tag:  reflect.StructTag(fmt.Sprintf(`json:"%s"`, field.Name)),
-			})
-		}
+// buildStructType - creates a struct type from the columns, nesting dotted names
+func buildStructType(cols []column) (typ reflect.Type, err error) {
+	defer recoverError(&err, ErrInvalidColumnName, "")
 
-		// Create struct type for the nested fields
-		parentStructs[parent] = reflect.StructOf(nestedFields)
-	}
+	return buildLevel(cols, 0), nil
+}
 
-	// Prepare all fields for the parent struct
-	for col, typ := range fieldTypes {
-		structFields = append(structFields, reflect.StructField{
-			Name: toTitle(col), // First letter uppercase
-			Type: typ,
-			Tag:  reflect.StructTag(fmt.Sprintf(`json:"%s"`, col)),
-		})
+// buildLevel - creates the struct type for one level of the dotted names
+func buildLevel(cols []column, depth int) reflect.Type {
+	var order []string
+	groups := make(map[string][]column)
+
+	// Group columns by their name at this level, keeping column order
+	for _, col := range cols {
+		name := col.path[depth]
+		if _, exists := groups[name]; !exists {
+			order = append(order, name)
+		}
+		groups[name] = append(groups[name], col)
 	}
 
-	// Add nested structs to the parent struct
-	for parent, typ := range parentStructs {
+	structFields := []reflect.StructField{}
+	for _, name := range order {
+		group := groups[name]
+		typ := group[0].typ
+		if len(group[0].path) > depth+1 {
+			// Nested field (e.g.: address.city)
+			typ = buildLevel(group, depth+1)
+		}
+
 		structFields = append(structFields, reflect.StructField{
-			Name: toTitle(parent), // First letter uppercase
+			Name: toTitle(name), // First letter uppercase
 			Type: typ,
-			Tag:  reflect.StructTag(fmt.Sprintf(`json:"%s"`, parent)),
+			Tag:  reflect.StructTag(fmt.Sprintf(`json:"%s"`, name)),
 		})
 	}
 
-	// Create the parent struct
-	structType := reflect.StructOf(structFields)
-	structValue := reflect.New(structType).Elem()
-
-	// Place values in the struct
-	for i, col := range columns {
-		val := values[i]
-		parts := strings.Split(col, ".")
-
-		// Convert byte array to the correct type
-		if byteArray, ok := val.([]byte); ok {
-			str := string(byteArray)
-			fieldType := fieldTypes[col]
-
-			if len(parts) > 1 {
-				// Check type for nested field
-				parent := parts[0]
-				child := parts[1]
-
-				for _, field := range fieldMap[parent] {
-					if field.Name == child {
-						fieldType = field.Type
-						break
-					}
-				}
-			}
+	return reflect.StructOf(structFields)
+}
 
-			switch fieldType.Kind() {
-			case reflect.Int:
-				if num, err := strconv.Atoi(str); err == nil {
-					val = num
-				}
-			case reflect.Float64:
-				if num, err := strconv.ParseFloat(str, 64); err == nil {
-					val = num
-				}
-			case reflect.Bool:
-				if b, err := strconv.ParseBool(str); err == nil {
-					val = b
-				}
-			default:
-				val = str
-			}
+// fillStruct - creates a value of the struct type and places the row values in it
+func fillStruct(structType reflect.Type, cols []column, values []any, cfg *config) (result *DBResult, err error) {
+	// Report the column being filled if a converter or reflect panics
+	current := ""
+	defer func() {
+		if r := recover(); r != nil {
+			err = newError(ErrReflection, current, fmt.Errorf("recovered panic: %v", r))
 		}
+	}()
 
-		if len(parts) > 1 {
-			// Assign value to nested field
-			parent := parts[0]
-			child := parts[1]
+	structValue := reflect.New(structType).Elem()
+	raw := make(map[string]any, len(cols))
 
-			// Get parent object
-			parentField := structValue.FieldByName(toTitle(parent))
+	for i, col := range cols {
+		current = col.name
+		raw[col.name] = values[i]
 
-			// Select nested field and assign value
-			childField := parentField.FieldByName(toTitle(child))
-			if childField.IsValid() && childField.CanSet() {
-				childField.Set(reflect.ValueOf(val))
-			}
-		} else {
-			// Assign value to parent field
-			field := structValue.FieldByName(toTitle(col))
-			if field.IsValid() && field.CanSet() {
-				field.Set(reflect.ValueOf(val))
+		val, err := col.convert(values[i], cfg)
+		if err != nil {
+			return nil, err
+		}
+		if val == nil {
+			// NULL keeps the zero value of the field
+			continue
+		}
+
+		// Walk down to the (possibly nested) field
+		field := structValue
+		for _, part := range col.path {
+			field = field.FieldByName(toTitle(part))
+			if !field.IsValid() {
+				break
 			}
 		}
+
+		if !field.IsValid() || !field.CanSet() {
+			continue
+		}
+		if actual := reflect.TypeOf(val); !actual.AssignableTo(field.Type()) {
+			return nil, newError(ErrTypeMismatch, col.name, fmt.Errorf("expected %s, got %s", field.Type(), actual))
+		}
+		field.Set(reflect.ValueOf(val))
 	}
 
 	return &DBResult{
 		value: structValue.Interface(),
 		typ:   structType,
+		raw:   raw,
 	}, nil
 }
 
+// createStruct - creates a dynamic struct with field types and values
+func createStruct(columns []string, values []any, fieldTypes map[string]reflect.Type, fieldMap map[string][]FieldInfo) (*DBResult, error) {
+	cols := make([]column, len(columns))
+	for i, name := range columns {
+		parts := strings.Split(name, ".")
+		cols[i] = column{name: name, path: parts, typ: fieldTypes[name]}
+
+		if len(parts) > 1 {
+			// Take the type of the nested field from its parent
+			for _, field := range fieldMap[parts[0]] {
+				if field.Name == parts[1] {
+					cols[i].typ = field.Type
+					break
+				}
+			}
+		}
+		if cols[i].typ == nil {
+			cols[i].typ = stringType
+		}
+	}
+
+	structType, err := buildStructType(cols)
+	if err != nil {
+		return nil, err
+	}
+
+	return fillStruct(structType, cols, values, defaultConfig())
+}
+
+// Metadata - returns how the fields of a query result were typed, or nil for results built in code
+func (dr *DBResult) Metadata() *Metadata {
+	if dr == nil {
+		return nil
+	}
+	return dr.meta
+}
+
 // Get - returns the value of a field in the struct
 func (dr *DBResult) Get(fieldName string) any {
+	val, _ := dr.Lookup(fieldName)
+	return val
+}
+
+// Lookup - returns the value of a field in the struct, or an ErrFieldNotFound error
+func (dr *DBResult) Lookup(fieldName string) (value any, err error) {
+	defer recoverError(&err, ErrReflection, fieldName)
+
 	parts := strings.Split(fieldName, ".")
 	val := reflect.ValueOf(dr.value)
 
 	// If there is a nested field, proceed
 	for _, part := range parts {
-		fieldName := toTitle(part) // First letter uppercase
-		field := val.FieldByName(fieldName)
+		if val.Kind() != reflect.Struct {
+			return nil, newError(ErrFieldNotFound, fieldName, nil)
+		}
 
+		field := val.FieldByName(toTitle(part)) // First letter uppercase
 		if !field.IsValid() {
-			return nil
+			return nil, newError(ErrFieldNotFound, fieldName, nil)
 		}
 
 		val = field
 	}
 
-	return val.Interface()
+	if !val.CanInterface() {
+		return nil, newError(ErrFieldNotFound, fieldName, nil)
+	}
+	return val.Interface(), nil
 }
 
 // GetString - returns the value as a string
@@ -384,3 +555,40 @@ func (dr *DBResult) GetBool(fieldName string) bool {
 
 	return false
 }
+
+// GetBytes - returns the value as a byte slice
+func (dr *DBResult) GetBytes(fieldName string) []byte {
+	switch v := dr.Get(fieldName).(type) {
+	case []byte:
+		return v
+	case string:
+		return []byte(v)
+	}
+
+	if b, ok := dr.GetRaw(fieldName).([]byte); ok {
+		return b
+	}
+
+	return nil
+}
+
+// GetRaw - returns the original driver value of a column before any conversion.
+// Results built in code have no driver values, so the field value is returned.
+func (dr *DBResult) GetRaw(fieldName string) any {
+	if dr == nil {
+		return nil
+	}
+	if val, ok := dr.raw[fieldName]; ok {
+		return val
+	}
+	return dr.Get(fieldName)
+}
+
+// IsNull - reports whether the column was NULL in the database
+func (dr *DBResult) IsNull(fieldName string) bool {
+	if dr == nil {
+		return false
+	}
+	val, ok := dr.raw[fieldName]
+	return ok && val == nil
+}
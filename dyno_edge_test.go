@@ -129,3 +129,43 @@ func TestFailedDBConnection(t *testing.T) {
 		t.Error("Expected an error with closed DB connection but got none")
 	}
 }
+
+func TestBinaryColumns(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Error creating mock database: %v", err)
+	}
+	defer db.Close()
+
+	payload := []byte{0xff, 0x00, '1', 0xfe}
+	rows := sqlmock.NewRowsWithColumnDefinition(
+		sqlmock.NewColumn("id").OfType("INT4", int64(0)),
+		sqlmock.NewColumn("data").OfType("BYTEA", []byte(nil)),
+		sqlmock.NewColumn("digits").OfType("BYTEA", []byte(nil)),
+		sqlmock.NewColumn("note").OfType("TEXT", ""),
+	).AddRow(int64(1), payload, []byte("42"), nil)
+	mock.ExpectQuery("SELECT").WillReturnRows(rows)
+
+	results, err := QueryToStruct(db, "SELECT id, data, digits, note FROM files")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result := results[0]
+
+	if got := result.GetBytes("data"); string(got) != string(payload) {
+		t.Errorf("GetBytes(data) = %v, want %v", got, payload)
+	}
+	// Binary columns must not be sniffed as numbers
+	if got, ok := result.Get("digits").([]byte); !ok || string(got) != "42" {
+		t.Errorf("Get(digits) = %#v, want []byte(\"42\")", result.Get("digits"))
+	}
+	if got := result.GetRaw("id"); got != int64(1) {
+		t.Errorf("GetRaw(id) = %#v, want int64(1)", got)
+	}
+	if got := result.GetRaw("note"); got != nil {
+		t.Errorf("GetRaw(note) = %#v, want nil", got)
+	}
+	if !result.IsNull("note") || result.IsNull("id") {
+		t.Errorf("IsNull(note) = %v, IsNull(id) = %v", result.IsNull("note"), result.IsNull("id"))
+	}
+}
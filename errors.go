@@ -0,0 +1,128 @@
+package godyno
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Sentinel errors, usable with errors.Is
+var (
+	ErrQuery             = errors.New("query failed")
+	ErrScan              = errors.New("row scan failed")
+	ErrRowIteration      = errors.New("row iteration failed")
+	ErrFieldNotFound     = errors.New("field not found")
+	ErrTypeMismatch      = errors.New("type mismatch")
+	ErrInvalidColumnName = errors.New("invalid column name")
+	ErrReflection        = errors.New("reflection failed")
+	ErrExpression        = errors.New("invalid expression")
+)
+
+// Error - describes a failure together with the column and row it happened at.
+// Kind is one of the sentinel errors and Err the underlying cause, if any;
+// errors.Is matches both. Row is the zero-based row index, or -1 when the
+// failure is not tied to a row.
+type Error struct {
+	Kind   error
+	Column string
+	Row    int
+	Err    error
+}
+
+// newError - creates an Error that is not tied to a row
+func newError(kind error, column string, err error) *Error {
+	return &Error{Kind: kind, Column: column, Row: -1, Err: err}
+}
+
+func (e *Error) Error() string {
+	var b strings.Builder
+	b.WriteString("godyno: ")
+	b.WriteString(e.Kind.Error())
+
+	sep := ": "
+	if e.Column != "" {
+		fmt.Fprintf(&b, "%scolumn %q", sep, e.Column)
+		sep = ", "
+	}
+	if e.Row >= 0 {
+		fmt.Fprintf(&b, "%srow %d", sep, e.Row)
+	}
+	if e.Err != nil {
+		b.WriteString(": ")
+		b.WriteString(e.Err.Error())
+	}
+
+	return b.String()
+}
+
+// Unwrap - exposes both the sentinel and the underlying cause
+func (e *Error) Unwrap() []error {
+	if e.Err == nil {
+		return []error{e.Kind}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// recoverError - turns a panic into an Error of the given kind.
+// It must be deferred directly: defer recoverError(&err, ErrReflection, column)
+func recoverError(err *error, kind error, column string) {
+	if r := recover(); r != nil {
+		*err = newError(kind, column, fmt.Errorf("recovered panic: %v", r))
+	}
+}
+
+// atRow - sets the row index of an Error inside err, if it has none yet
+func atRow(err error, row int) error {
+	var e *Error
+	if errors.As(err, &e) && e.Row < 0 {
+		e.Row = row
+	}
+	return err
+}
+
+// validateColumns - checks that every column can become a struct field
+func validateColumns(cols []column) error {
+	seen := make(map[string]string)
+	leaves := make(map[string]bool)
+
+	for _, col := range cols {
+		goPath := ""
+		for depth, part := range col.path {
+			if !isIdentifier(toTitle(part)) {
+				return newError(ErrInvalidColumnName, col.name, fmt.Errorf("%q is not a valid field name", part))
+			}
+
+			goPath += "." + toTitle(part)
+			leaf := depth == len(col.path)-1
+
+			// Two columns may not map to the same Go field (e.g. "id" and "Id"),
+			// and a field can't be both a value and a parent (e.g. "a" and "a.b")
+			if other, ok := seen[goPath]; ok && (leaf || leaves[goPath]) {
+				return newError(ErrInvalidColumnName, col.name, fmt.Errorf("conflicts with column %q", other))
+			}
+			seen[goPath] = col.name
+			if leaf {
+				leaves[goPath] = true
+			}
+		}
+	}
+
+	return nil
+}
+
+// isIdentifier - reports whether s is a valid exported Go identifier
+func isIdentifier(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i, r := range s {
+		if i == 0 && !unicode.IsUpper(r) {
+			return false
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' {
+			return false
+		}
+	}
+	return true
+}
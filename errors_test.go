@@ -0,0 +1,130 @@
+package godyno
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestErrors(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	t.Run("Query error", func(t *testing.T) {
+		cause := errors.New("table does not exist")
+		mock.ExpectQuery("SELECT").WillReturnError(cause)
+
+		_, err := QueryToStruct(db, "SELECT * FROM missing")
+		if !errors.Is(err, ErrQuery) || !errors.Is(err, cause) {
+			t.Errorf("Expected ErrQuery wrapping the cause, got %v", err)
+		}
+	})
+
+	t.Run("Row iteration error", func(t *testing.T) {
+		mock.ExpectQuery("SELECT").WillReturnRows(
+			sqlmock.NewRows([]string{"id"}).
+				AddRow(1).
+				AddRow(2).
+				RowError(1, errors.New("connection reset")),
+		)
+
+		_, err := QueryToStruct(db, "SELECT id FROM products")
+		if !errors.Is(err, ErrRowIteration) {
+			t.Errorf("Expected ErrRowIteration, got %v", err)
+		}
+	})
+
+	t.Run("Type mismatch", func(t *testing.T) {
+		mock.ExpectQuery("SELECT").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "price"}).
+				AddRow(1, []byte("10")).
+				AddRow(2, []byte("free")),
+		)
+
+		_, err := QueryToStruct(db, "SELECT id, price FROM products")
+		if !errors.Is(err, ErrTypeMismatch) {
+			t.Fatalf("Expected ErrTypeMismatch, got %v", err)
+		}
+
+		var e *Error
+		if !errors.As(err, &e) {
+			t.Fatalf("Expected *Error, got %T", err)
+		}
+		if e.Column != "price" || e.Row != 1 {
+			t.Errorf("Error column = %q, row = %d, want price and 1", e.Column, e.Row)
+		}
+		if want := `godyno: type mismatch: column "price", row 1: cannot convert "free" to int`; err.Error() != want {
+			t.Errorf("Error() = %q, want %q", err.Error(), want)
+		}
+	})
+
+	t.Run("Invalid column name", func(t *testing.T) {
+		mock.ExpectQuery("SELECT").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "count(*)"}).AddRow(1, 2),
+		)
+
+		_, err := QueryToStruct(db, "SELECT id, count(*) FROM products")
+		if !errors.Is(err, ErrInvalidColumnName) {
+			t.Errorf("Expected ErrInvalidColumnName, got %v", err)
+		}
+	})
+
+	t.Run("Field not found", func(t *testing.T) {
+		mock.ExpectQuery("SELECT").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "address.city"}).AddRow(1, "Izmir"),
+		)
+
+		results, err := QueryToStruct(db, "SELECT id, city AS address.city FROM products")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+
+		if _, err := results[0].Lookup("address.zip"); !errors.Is(err, ErrFieldNotFound) {
+			t.Errorf("Lookup(address.zip) error = %v, want ErrFieldNotFound", err)
+		}
+		if _, err := results[0].Lookup("id.value"); !errors.Is(err, ErrFieldNotFound) {
+			t.Errorf("Lookup(id.value) error = %v, want ErrFieldNotFound", err)
+		}
+		if val, err := results[0].Lookup("address.city"); err != nil || val != "Izmir" {
+			t.Errorf("Lookup(address.city) = %v, %v", val, err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestValidateColumns(t *testing.T) {
+	testCases := []struct {
+		columns []string
+		valid   bool
+	}{
+		{[]string{"id", "address.city", "address.zip"}, true},
+		{[]string{"id", "Id"}, false},
+		{[]string{"address", "address.city"}, false},
+		{[]string{"address.city", "address"}, false},
+		{[]string{"a..b"}, false},
+		{[]string{""}, false},
+		{[]string{"first name"}, false},
+		{[]string{"_hidden"}, false},
+		{[]string{"şehir"}, true},
+	}
+
+	for _, tc := range testCases {
+		cols := make([]column, len(tc.columns))
+		for i, name := range tc.columns {
+			cols[i] = column{name: name, path: strings.Split(name, ".")}
+		}
+
+		err := validateColumns(cols)
+		if tc.valid && err != nil {
+			t.Errorf("validateColumns(%q) = %v, want nil", tc.columns, err)
+		}
+		if !tc.valid && !errors.Is(err, ErrInvalidColumnName) {
+			t.Errorf("validateColumns(%q) = %v, want ErrInvalidColumnName", tc.columns, err)
+		}
+	}
+}
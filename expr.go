@@ -0,0 +1,702 @@
+package godyno
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Expr - a compiled expression over the fields of a DBResult, e.g.
+// `active && price > 10 && address.city == "Izmir"`.
+//
+// Identifiers are resolved with the dotted Get paths and keep the inferred
+// field types; NULL columns evaluate to null. Supported are the literals
+// numbers, "strings" or 'strings', true, false and null; the operators
+// || && ! (also or, and, not), == != < <= > >=, + (numbers and strings),
+// - * / % and parentheses; and the functions len, lower, upper and
+// contains. Division always yields a float64. Logical operators require
+// booleans and comparisons require comparable types, otherwise evaluation
+// fails with ErrTypeMismatch.
+//
+// null is treated as false wherever a boolean is expected: by && || !, and
+// by Match and FilterExpr. Ordering comparisons with null are false, so
+// `active && qty > 1` simply skips rows where active or qty is NULL, and
+// `!active` matches them. Use `active == null` to select NULLs explicitly.
+type Expr struct {
+	src  string
+	root exprNode
+}
+
+// CompileExpr - parses an expression; syntax errors wrap ErrExpression
+func CompileExpr(src string) (*Expr, error) {
+	p := &exprParser{src: src}
+	if err := p.tokenize(); err != nil {
+		return nil, err
+	}
+
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, p.errorf(tok, "unexpected %q", tok.text)
+	}
+
+	return &Expr{src: src, root: root}, nil
+}
+
+// String - returns the source of the expression
+func (e *Expr) String() string {
+	return e.src
+}
+
+// Eval - evaluates the expression against a result.
+// Integers are returned as int64 and other numbers as float64.
+func (e *Expr) Eval(dr *DBResult) (value any, err error) {
+	defer recoverError(&err, ErrReflection, "")
+
+	return e.root.eval(dr)
+}
+
+// Match - evaluates the expression and requires a boolean result
+func (e *Expr) Match(dr *DBResult) (bool, error) {
+	val, err := e.Eval(dr)
+	if err != nil {
+		return false, err
+	}
+
+	if val == nil {
+		return false, nil // null is false
+	}
+	b, ok := val.(bool)
+	if !ok {
+		return false, newError(ErrTypeMismatch, "", fmt.Errorf("%s: expected bool, got %T", e.src, val))
+	}
+	return b, nil
+}
+
+// FilterExpr - returns the rows for which the expression is true
+func (rs ResultSet) FilterExpr(src string) (ResultSet, error) {
+	e, err := CompileExpr(src)
+	if err != nil {
+		return nil, err
+	}
+
+	out := ResultSet{}
+	for i, dr := range rs {
+		ok, err := e.Match(dr)
+		if err != nil {
+			return nil, atRow(err, i)
+		}
+		if ok {
+			out = append(out, dr)
+		}
+	}
+	return out, nil
+}
+
+// Compute - sets field on every row to the value of the expression, e.g.
+// Compute("total", "price * quantity"). The rows are changed in place.
+func (rs ResultSet) Compute(field, src string) (ResultSet, error) {
+	e, err := CompileExpr(src)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, dr := range rs {
+		val, err := e.Eval(dr)
+		if err != nil {
+			return nil, atRow(err, i)
+		}
+		if err := dr.Set(field, val); err != nil {
+			return nil, atRow(err, i)
+		}
+	}
+	return rs, nil
+}
+
+// Tokens
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokString
+	tokIdent
+	tokOp
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// exprParser - a recursive descent parser producing exprNodes
+type exprParser struct {
+	src    string
+	tokens []token
+	pos    int
+}
+
+// errorf - returns a syntax error at a token
+func (p *exprParser) errorf(tok token, format string, args ...any) error {
+	return newError(ErrExpression, "", fmt.Errorf("position %d: %s", tok.pos+1, fmt.Sprintf(format, args...)))
+}
+
+// tokenize - splits the source into tokens
+func (p *exprParser) tokenize() error {
+	src := p.src
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c >= '0' && c <= '9':
+			start := i
+			for i < len(src) && (isDigit(src[i]) || src[i] == '.' ||
+				src[i] == 'e' || src[i] == 'E' ||
+				((src[i] == '+' || src[i] == '-') && (src[i-1] == 'e' || src[i-1] == 'E'))) {
+				i++
+			}
+			p.tokens = append(p.tokens, token{kind: tokNumber, text: src[start:i], pos: start})
+		case c == '"' || c == '\'':
+			start := i
+			var b strings.Builder
+			for i++; i < len(src) && src[i] != c; i++ {
+				if src[i] == '\\' && i+1 < len(src) {
+					i++
+					switch src[i] {
+					case 'n':
+						b.WriteByte('\n')
+					case 't':
+						b.WriteByte('\t')
+					default:
+						b.WriteByte(src[i])
+					}
+					continue
+				}
+				b.WriteByte(src[i])
+			}
+			if i >= len(src) {
+				return p.errorf(token{pos: start}, "unterminated string")
+			}
+			i++
+			p.tokens = append(p.tokens, token{kind: tokString, text: b.String(), pos: start})
+		case c == '_' || unicode.IsLetter(rune(c)) || c >= 0x80:
+			start := i
+			for i < len(src) && (src[i] == '_' || src[i] == '.' || isDigit(src[i]) ||
+				unicode.IsLetter(rune(src[i])) || src[i] >= 0x80) {
+				i++
+			}
+			p.tokens = append(p.tokens, token{kind: tokIdent, text: src[start:i], pos: start})
+		default:
+			op := ""
+			for _, candidate := range []string{"&&", "||", "==", "!=", "<=", ">=", "<", ">", "!", "+", "-", "*", "/", "%", "(", ")", ","} {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return p.errorf(token{pos: i}, "unexpected character %q", c)
+			}
+			p.tokens = append(p.tokens, token{kind: tokOp, text: op, pos: i})
+			i += len(op)
+		}
+	}
+
+	p.tokens = append(p.tokens, token{kind: tokEOF, pos: len(src)})
+	return nil
+}
+
+// isDigit - reports whether c is an ASCII digit
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func (p *exprParser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *exprParser) next() token {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept - consumes the next token if it is one of the operators or keywords
+func (p *exprParser) accept(ops ...string) (string, bool) {
+	tok := p.peek()
+	if tok.kind != tokOp && tok.kind != tokIdent {
+		return "", false
+	}
+	for _, op := range ops {
+		if tok.text == op {
+			p.next()
+			return op, true
+		}
+	}
+	return "", false
+}
+
+func (p *exprParser) parseOr() (exprNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("||", "or"); !ok {
+			return left, nil
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{op: "||", left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseAnd() (exprNode, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		if _, ok := p.accept("&&", "and"); !ok {
+			return left, nil
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		left = logicalNode{op: "&&", left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseNot() (exprNode, error) {
+	if _, ok := p.accept("!", "not"); ok {
+		operand, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand: operand}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *exprParser) parseComparison() (exprNode, error) {
+	left, err := p.parseAdditive()
+	if err != nil {
+		return nil, err
+	}
+	if op, ok := p.accept("==", "!=", "<=", ">=", "<", ">"); ok {
+		right, err := p.parseAdditive()
+		if err != nil {
+			return nil, err
+		}
+		return compareNode{op: op, left: left, right: right}, nil
+	}
+	return left, nil
+}
+
+func (p *exprParser) parseAdditive() (exprNode, error) {
+	left, err := p.parseMultiplicative()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("+", "-")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseMultiplicative()
+		if err != nil {
+			return nil, err
+		}
+		left = arithNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseMultiplicative() (exprNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		op, ok := p.accept("*", "/", "%")
+		if !ok {
+			return left, nil
+		}
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = arithNode{op: op, left: left, right: right}
+	}
+}
+
+func (p *exprParser) parseUnary() (exprNode, error) {
+	if _, ok := p.accept("-"); ok {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return arithNode{op: "-", left: literalNode{value: int64(0)}, right: operand}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *exprParser) parsePrimary() (exprNode, error) {
+	tok := p.next()
+
+	switch tok.kind {
+	case tokNumber:
+		if n, err := strconv.ParseInt(tok.text, 10, 64); err == nil {
+			return literalNode{value: n}, nil
+		}
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return nil, p.errorf(tok, "invalid number %q", tok.text)
+		}
+		return literalNode{value: f}, nil
+	case tokString:
+		return literalNode{value: tok.text}, nil
+	case tokIdent:
+		switch tok.text {
+		case "true":
+			return literalNode{value: true}, nil
+		case "false":
+			return literalNode{value: false}, nil
+		case "null", "nil":
+			return literalNode{value: nil}, nil
+		}
+		if _, ok := p.accept("("); ok {
+			return p.parseCall(tok)
+		}
+		return fieldNode{path: tok.text}, nil
+	case tokOp:
+		if tok.text == "(" {
+			inner, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			if _, ok := p.accept(")"); !ok {
+				return nil, p.errorf(p.peek(), "expected )")
+			}
+			return inner, nil
+		}
+	case tokEOF:
+		return nil, p.errorf(tok, "unexpected end of expression")
+	}
+
+	return nil, p.errorf(tok, "unexpected %q", tok.text)
+}
+
+// parseCall - parses the arguments of a function call after its "("
+func (p *exprParser) parseCall(name token) (exprNode, error) {
+	fn, ok := exprFuncs[name.text]
+	if !ok {
+		return nil, p.errorf(name, "unknown function %q", name.text)
+	}
+
+	call := callNode{name: name.text, fn: fn.fn}
+	if _, ok := p.accept(")"); !ok {
+		for {
+			arg, err := p.parseOr()
+			if err != nil {
+				return nil, err
+			}
+			call.args = append(call.args, arg)
+
+			if _, ok := p.accept(")"); ok {
+				break
+			}
+			if _, ok := p.accept(","); !ok {
+				return nil, p.errorf(p.peek(), "expected , or )")
+			}
+		}
+	}
+
+	if len(call.args) != fn.arity {
+		return nil, p.errorf(name, "%s expects %d argument(s), got %d", name.text, fn.arity, len(call.args))
+	}
+	return call, nil
+}
+
+// Nodes
+
+type exprNode interface {
+	eval(dr *DBResult) (any, error)
+}
+
+type literalNode struct {
+	value any
+}
+
+func (n literalNode) eval(*DBResult) (any, error) {
+	return n.value, nil
+}
+
+type fieldNode struct {
+	path string
+}
+
+func (n fieldNode) eval(dr *DBResult) (any, error) {
+	val, err := dr.Lookup(n.path)
+	if err != nil {
+		return nil, err
+	}
+	if dr.IsNull(n.path) {
+		return nil, nil
+	}
+	return normalizeNumber(val), nil
+}
+
+type notNode struct {
+	operand exprNode
+}
+
+func (n notNode) eval(dr *DBResult) (any, error) {
+	b, err := evalBool(n.operand, dr, "!")
+	if err != nil {
+		return nil, err
+	}
+	return !b, nil
+}
+
+type logicalNode struct {
+	op          string
+	left, right exprNode
+}
+
+func (n logicalNode) eval(dr *DBResult) (any, error) {
+	left, err := evalBool(n.left, dr, n.op)
+	if err != nil {
+		return nil, err
+	}
+
+	// Short circuit
+	if (n.op == "&&" && !left) || (n.op == "||" && left) {
+		return left, nil
+	}
+	return evalBool(n.right, dr, n.op)
+}
+
+type compareNode struct {
+	op          string
+	left, right exprNode
+}
+
+func (n compareNode) eval(dr *DBResult) (any, error) {
+	left, err := n.left.eval(dr)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(dr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only equality is defined for null
+	if (left == nil || right == nil) && n.op != "==" && n.op != "!=" {
+		return false, nil
+	}
+
+	c, ok := compareValues(left, right)
+	if !ok {
+		return nil, typeError(n.op, left, right)
+	}
+
+	switch n.op {
+	case "==":
+		return c == 0, nil
+	case "!=":
+		return c != 0, nil
+	case "<":
+		return c < 0, nil
+	case "<=":
+		return c <= 0, nil
+	case ">":
+		return c > 0, nil
+	}
+	return c >= 0, nil
+}
+
+type arithNode struct {
+	op          string
+	left, right exprNode
+}
+
+func (n arithNode) eval(dr *DBResult) (any, error) {
+	left, err := n.left.eval(dr)
+	if err != nil {
+		return nil, err
+	}
+	right, err := n.right.eval(dr)
+	if err != nil {
+		return nil, err
+	}
+
+	// Arithmetic with null gives null, as in SQL
+	if left == nil || right == nil {
+		return nil, nil
+	}
+
+	if n.op == "+" {
+		if ls, ok := left.(string); ok {
+			if rs, ok := right.(string); ok {
+				return ls + rs, nil
+			}
+		}
+	}
+
+	li, lInt := left.(int64)
+	ri, rInt := right.(int64)
+	lf, lNum := toNumber(left)
+	rf, rNum := toNumber(right)
+	if !lNum || !rNum {
+		return nil, typeError(n.op, left, right)
+	}
+
+	switch n.op {
+	case "+":
+		if lInt && rInt {
+			return li + ri, nil
+		}
+		return lf + rf, nil
+	case "-":
+		if lInt && rInt {
+			return li - ri, nil
+		}
+		return lf - rf, nil
+	case "*":
+		if lInt && rInt {
+			return li * ri, nil
+		}
+		return lf * rf, nil
+	case "/":
+		if rf == 0 {
+			return nil, newError(ErrTypeMismatch, "", fmt.Errorf("division by zero"))
+		}
+		return lf / rf, nil
+	}
+
+	// %
+	if !lInt || !rInt {
+		return nil, typeError(n.op, left, right)
+	}
+	if ri == 0 {
+		return nil, newError(ErrTypeMismatch, "", fmt.Errorf("division by zero"))
+	}
+	return li % ri, nil
+}
+
+type callNode struct {
+	name string
+	fn   func(args []any) (any, error)
+	args []exprNode
+}
+
+func (n callNode) eval(dr *DBResult) (any, error) {
+	args := make([]any, len(n.args))
+	for i, arg := range n.args {
+		val, err := arg.eval(dr)
+		if err != nil {
+			return nil, err
+		}
+		args[i] = val
+	}
+
+	val, err := n.fn(args)
+	if err != nil {
+		return nil, newError(ErrTypeMismatch, "", fmt.Errorf("%s: %w", n.name, err))
+	}
+	return val, nil
+}
+
+// exprFuncs - the functions available in expressions
+var exprFuncs = map[string]struct {
+	arity int
+	fn    func(args []any) (any, error)
+}{
+	"len": {1, func(args []any) (any, error) {
+		switch v := args[0].(type) {
+		case nil:
+			return int64(0), nil
+		case string:
+			return int64(len([]rune(v))), nil
+		case []byte:
+			return int64(len(v)), nil
+		}
+		return nil, fmt.Errorf("expected string, got %T", args[0])
+	}},
+	"lower": {1, stringFunc(strings.ToLower)},
+	"upper": {1, stringFunc(strings.ToUpper)},
+	"contains": {2, func(args []any) (any, error) {
+		s, ok := args[0].(string)
+		sub, subOK := args[1].(string)
+		if !ok || !subOK {
+			return nil, fmt.Errorf("expected strings, got %T and %T", args[0], args[1])
+		}
+		return strings.Contains(s, sub), nil
+	}},
+}
+
+// stringFunc - wraps a string function for expressions; null stays null
+func stringFunc(fn func(string) string) func(args []any) (any, error) {
+	return func(args []any) (any, error) {
+		switch v := args[0].(type) {
+		case nil:
+			return nil, nil
+		case string:
+			return fn(v), nil
+		}
+		return nil, fmt.Errorf("expected string, got %T", args[0])
+	}
+}
+
+// evalBool - evaluates a node that must produce a boolean; null is false
+func evalBool(n exprNode, dr *DBResult, op string) (bool, error) {
+	val, err := n.eval(dr)
+	if err != nil || val == nil {
+		return false, err
+	}
+
+	b, ok := val.(bool)
+	if !ok {
+		return false, newError(ErrTypeMismatch, "", fmt.Errorf("%s expects bool, got %T", op, val))
+	}
+	return b, nil
+}
+
+// typeError - reports operands an operator can't be applied to
+func typeError(op string, left, right any) error {
+	return newError(ErrTypeMismatch, "", fmt.Errorf("cannot apply %s to %T and %T", op, left, right))
+}
+
+// normalizeNumber - returns integers as int64 and floats as float64
+func normalizeNumber(val any) any {
+	v := reflect.ValueOf(val)
+	if !isNumeric(v.Type()) {
+		return val
+	}
+	if isFloat(v.Type()) {
+		return v.Float()
+	}
+	return toInt64(v)
+}
+
+// toNumber - returns a numeric value as float64
+func toNumber(val any) (float64, bool) {
+	v := reflect.ValueOf(val)
+	if val == nil || !isNumeric(v.Type()) {
+		return 0, false
+	}
+	return toFloat64(v), true
+}
@@ -0,0 +1,156 @@
+package godyno
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestExprEval(t *testing.T) {
+	dr, err := FromMap(map[string]any{
+		"active":   true,
+		"price":    12.5,
+		"quantity": int64(3),
+		"title":    "Desk Lamp",
+		"note":     nil,
+		"address":  map[string]any{"city": "Izmir"},
+	})
+	if err != nil {
+		t.Fatalf("FromMap() error = %v", err)
+	}
+
+	testCases := []struct {
+		src  string
+		want any
+	}{
+		{`active && price > 10 && address.city == "Izmir"`, true},
+		{`!active || quantity >= 5`, false},
+		{`not active or quantity < 5`, true},
+		{`price * quantity`, 37.5},
+		{`quantity * 2 + 1`, int64(7)},
+		{`quantity % 2`, int64(1)},
+		{`quantity / 2`, 1.5},
+		{`-(quantity - 5)`, int64(2)},
+		{`title + "!"`, "Desk Lamp!"},
+		{`lower(title) == 'desk lamp'`, true},
+		{`contains(upper(title), "LAMP")`, true},
+		{`len(address.city)`, int64(5)},
+		{`note == null`, true},
+		{`note != null`, false},
+		{`note > 1`, false},
+		{`price + note`, nil},
+		{`1.5e1`, 15.0},
+	}
+
+	for _, tc := range testCases {
+		e, err := CompileExpr(tc.src)
+		if err != nil {
+			t.Errorf("CompileExpr(%q) error = %v", tc.src, err)
+			continue
+		}
+		got, err := e.Eval(dr)
+		if err != nil {
+			t.Errorf("Eval(%q) error = %v", tc.src, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("Eval(%q) = %#v, want %#v", tc.src, got, tc.want)
+		}
+	}
+}
+
+func TestExprErrors(t *testing.T) {
+	dr, _ := FromMap(map[string]any{"price": 10, "title": "x"})
+
+	syntax := []string{`price >`, `(price`, `"open`, `price # 2`, `foo(1)`, `len(1, 2)`, `price price`}
+	for _, src := range syntax {
+		if _, err := CompileExpr(src); !errors.Is(err, ErrExpression) {
+			t.Errorf("CompileExpr(%q) error = %v, want ErrExpression", src, err)
+		}
+	}
+
+	evaluation := []struct {
+		src  string
+		want error
+	}{
+		{`price && true`, ErrTypeMismatch},
+		{`title > 5`, ErrTypeMismatch},
+		{`title - 1`, ErrTypeMismatch},
+		{`price / 0`, ErrTypeMismatch},
+		{`missing == 1`, ErrFieldNotFound},
+	}
+	for _, tc := range evaluation {
+		e, err := CompileExpr(tc.src)
+		if err != nil {
+			t.Fatalf("CompileExpr(%q) error = %v", tc.src, err)
+		}
+		if _, err := e.Eval(dr); !errors.Is(err, tc.want) {
+			t.Errorf("Eval(%q) error = %v, want %v", tc.src, err, tc.want)
+		}
+	}
+
+	e, _ := CompileExpr("price + 1")
+	if _, err := e.Match(dr); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("Match() of a number = %v, want ErrTypeMismatch", err)
+	}
+}
+
+func TestResultSetExpr(t *testing.T) {
+	rs := productSet(t)
+
+	filtered, err := rs.FilterExpr(`active && category == "tech" && price < 40`)
+	if err != nil {
+		t.Fatalf("FilterExpr() error = %v", err)
+	}
+	if got := filtered.Pluck("title"); !reflect.DeepEqual(got, []any{"Mouse"}) {
+		t.Errorf("FilterExpr() = %v, want [Mouse]", got)
+	}
+
+	computed, err := rs.Compute("discounted", "price * 0.5")
+	if err != nil {
+		t.Fatalf("Compute() error = %v", err)
+	}
+	if got := computed[1].GetFloat("discounted"); got != 99.5 {
+		t.Errorf("Compute() discounted = %v, want 99.5", got)
+	}
+
+	_, err = rs.FilterExpr(`price`)
+	var e *Error
+	if !errors.As(err, &e) || e.Row != 0 {
+		t.Errorf("FilterExpr(price) error = %v, want a type mismatch at row 0", err)
+	}
+}
+
+func TestExprNull(t *testing.T) {
+	rs := newTestSet(t,
+		map[string]any{"id": int64(1), "active": nil, "qty": int64(5)},
+		map[string]any{"id": int64(2), "active": true, "qty": int64(3)},
+		map[string]any{"id": int64(3), "active": true, "qty": nil},
+		map[string]any{"id": int64(4), "active": false, "qty": int64(9)},
+	)
+
+	testCases := []struct {
+		src  string
+		want []any
+	}{
+		{`active && qty > 1`, []any{int64(2)}},
+		{`active || qty > 4`, []any{int64(1), int64(2), int64(3), int64(4)}},
+		{`!active`, []any{int64(1), int64(4)}},
+		{`active`, []any{int64(2), int64(3)}},
+		{`active == null`, []any{int64(1)}},
+	}
+	for _, tc := range testCases {
+		filtered, err := rs.FilterExpr(tc.src)
+		if err != nil {
+			t.Fatalf("FilterExpr(%q) error = %v", tc.src, err)
+		}
+		if got := filtered.Pluck("id"); !reflect.DeepEqual(got, tc.want) {
+			t.Errorf("FilterExpr(%q) = %v, want %v", tc.src, got, tc.want)
+		}
+	}
+
+	e, _ := CompileExpr("active")
+	if ok, err := e.Match(rs[0]); ok || err != nil {
+		t.Errorf("Match() of a NULL = %v, %v, want false, nil", ok, err)
+	}
+}
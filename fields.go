@@ -0,0 +1,87 @@
+package godyno
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Field - describes a field of a DBResult.
+// Column is the original column name (e.g. "address.city"), Name the Go
+// field name (e.g. "City") and Path the nested path (e.g. ["address", "city"]).
+type Field struct {
+	Column   string
+	Name     string
+	Path     []string
+	Type     reflect.Type
+	Nullable bool
+}
+
+// Fields - returns the leaf fields in column order; nested structs are descended into
+func (dr *DBResult) Fields() []Field {
+	if dr == nil || dr.typ == nil {
+		return nil
+	}
+
+	var fields []Field
+	eachField(dr.typ, nil, func(path []string, sf reflect.StructField) {
+		column := strings.Join(path, ".")
+		fields = append(fields, Field{
+			Column:   column,
+			Name:     sf.Name,
+			Path:     path,
+			Type:     sf.Type,
+			Nullable: dr.nullable(column),
+		})
+	})
+
+	return fields
+}
+
+// Has - reports whether the field exists; parents of nested fields (e.g. "address") count too
+func (dr *DBResult) Has(fieldName string) bool {
+	_, err := dr.Lookup(fieldName)
+	return err == nil
+}
+
+// Type - returns the dynamic struct type, or nil for an empty result
+func (dr *DBResult) Type() reflect.Type {
+	if dr == nil {
+		return nil
+	}
+	return dr.typ
+}
+
+// Len - returns the number of leaf fields, i.e. the number of columns
+func (dr *DBResult) Len() int {
+	return len(dr.Fields())
+}
+
+// nullable - reports whether a column may hold NULL. The metadata only knows the
+// sampled rows, so a NULL in this row counts as well.
+func (dr *DBResult) nullable(column string) bool {
+	if col, ok := dr.meta.Column(column); ok && col.Nullable {
+		return true
+	}
+	return dr.IsNull(column)
+}
+
+// eachField - calls fn for every leaf field of a struct type in order, with its path of column names
+func eachField(t reflect.Type, prefix []string, fn func(path []string, sf reflect.StructField)) {
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		path := append(append([]string{}, prefix...), fieldKey(sf))
+		if isNested(sf.Type) {
+			eachField(sf.Type, path, fn)
+			continue
+		}
+		fn(path, sf)
+	}
+}
@@ -0,0 +1,84 @@
+package godyno
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestFields(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	rows := sqlmock.NewRowsWithColumnDefinition(
+		sqlmock.NewColumn("id").OfType("INT4", int64(0)).Nullable(false),
+		sqlmock.NewColumn("title").OfType("TEXT", ""),
+		sqlmock.NewColumn("address.city").OfType("TEXT", ""),
+		sqlmock.NewColumn("address.zip").OfType("TEXT", "").Nullable(true),
+		sqlmock.NewColumn("active").OfType("BOOL", false),
+	).AddRow(int64(1), "Product 1", nil, "35000", true)
+	mock.ExpectQuery("SELECT").WillReturnRows(rows)
+
+	results, err := QueryToStruct(db, "SELECT * FROM products")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	result := results[0]
+
+	fields := result.Fields()
+	want := []Field{
+		{Column: "id", Name: "Id", Path: []string{"id"}, Type: reflect.TypeOf(int64(0))},
+		{Column: "title", Name: "Title", Path: []string{"title"}, Type: stringType},
+		{Column: "address.city", Name: "City", Path: []string{"address", "city"}, Type: stringType, Nullable: true},
+		{Column: "address.zip", Name: "Zip", Path: []string{"address", "zip"}, Type: stringType, Nullable: true},
+		{Column: "active", Name: "Active", Path: []string{"active"}, Type: boolType},
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("Fields() =\n%+v\nwant\n%+v", fields, want)
+	}
+
+	if result.Len() != 5 {
+		t.Errorf("Len() = %d, want 5", result.Len())
+	}
+	if !result.Has("address") || !result.Has("address.zip") || result.Has("address.street") {
+		t.Error("Has() reported wrong fields")
+	}
+	if result.Type().Kind() != reflect.Struct || result.Type().NumField() != 4 {
+		t.Errorf("Type() = %v, want a struct with 4 top level fields", result.Type())
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestFieldsNullAfterSample(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "note"}).
+			AddRow(int64(1), "first").
+			AddRow(int64(2), nil),
+	)
+	results, err := QueryToStruct(db, "SELECT * FROM products")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if fields := results[1].Fields(); fields[0].Nullable || !fields[1].Nullable {
+		t.Errorf("Fields() of the second row = %+v, want only note nullable", fields)
+	}
+	src, err := GenerateStruct("models", "Product", results[1])
+	if err != nil || !strings.Contains(string(src), "*string") {
+		t.Errorf("GenerateStruct() = %s, %v, want a *string note", src, err)
+	}
+}
+
+func TestFieldsEmpty(t *testing.T) {
+	if New().Fields() != nil || New().Len() != 0 || New().Type() != nil {
+		t.Error("An empty result should have no fields")
+	}
+}
@@ -0,0 +1,381 @@
+package godyno
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ParamType - the type a query parameter is parsed and validated as
+type ParamType int
+
+const (
+	// ParamString passes the value unchanged
+	ParamString ParamType = iota
+	// ParamInt parses the value as an int64
+	ParamInt
+	// ParamFloat parses the value as a float64
+	ParamFloat
+	// ParamBool parses the value with strconv.ParseBool
+	ParamBool
+	// ParamTime parses the value as RFC 3339 or as a date (2006-01-02)
+	ParamTime
+)
+
+// String - returns the name of the type used in error messages
+func (t ParamType) String() string {
+	switch t {
+	case ParamInt:
+		return "integer"
+	case ParamFloat:
+		return "number"
+	case ParamBool:
+		return "boolean"
+	case ParamTime:
+		return "time"
+	}
+	return "string"
+}
+
+// Param - a URL query parameter of a NamedQuery. A missing optional
+// parameter without Default is passed to the database as NULL.
+type Param struct {
+	Name     string
+	Type     ParamType
+	Required bool
+	// Default is used when the parameter is missing, parsed like a request value
+	Default string
+}
+
+// NamedQuery - a parameterized query served by a Handler.
+// Params are passed to the query as positional arguments in order.
+type NamedQuery struct {
+	Name   string
+	SQL    string
+	Params []Param
+	// Paginate wraps the query with LIMIT and OFFSET taken from the
+	// limit and offset request parameters
+	Paginate bool
+	// Options are passed to QueryToStruct, e.g. WithSampleRows
+	Options []Option
+}
+
+// Handler - serves a registry of named queries as JSON, NDJSON or CSV.
+// The query name is the request path without slashes, so handlers are
+// usually mounted with http.StripPrefix:
+//
+//	h := godyno.NewHandler(db)
+//	h.Register(godyno.NamedQuery{Name: "products", SQL: "SELECT * FROM products WHERE category_id = $1",
+//		Params: []godyno.Param{{Name: "category", Type: godyno.ParamInt, Required: true}}, Paginate: true})
+//	http.Handle("/api/", http.StripPrefix("/api/", h))
+//
+// The format is chosen by the format parameter (json, ndjson, csv) or the
+// Accept header. Queries run with the request context, so they are
+// cancelled when the client goes away.
+type Handler struct {
+	// DefaultLimit is the page size when limit is not given, 100 by default
+	DefaultLimit int
+	// MaxLimit is the largest accepted limit, 1000 by default
+	MaxLimit int
+	// ErrorLog receives query errors, which are not sent to the client
+	ErrorLog func(r *http.Request, err error)
+
+	db      *sql.DB
+	queries map[string]NamedQuery
+}
+
+// reserved request parameters that are not passed to queries
+var handlerParams = map[string]bool{"limit": true, "offset": true, "format": true}
+
+// handlerFormats - content types by format name
+var handlerFormats = map[string]string{
+	"json":   "application/json",
+	"ndjson": "application/x-ndjson",
+	"csv":    "text/csv",
+}
+
+// NewHandler - returns a Handler running queries on db
+func NewHandler(db *sql.DB) *Handler {
+	return &Handler{DefaultLimit: 100, MaxLimit: 1000, db: db, queries: map[string]NamedQuery{}}
+}
+
+// Register - adds a query to the registry
+func (h *Handler) Register(q NamedQuery) error {
+	if q.Name == "" || strings.Contains(q.Name, "/") {
+		return fmt.Errorf("invalid query name %q", q.Name)
+	}
+	if h.queries == nil {
+		h.queries = map[string]NamedQuery{}
+	}
+	if _, ok := h.queries[q.Name]; ok {
+		return fmt.Errorf("query %q is already registered", q.Name)
+	}
+
+	for i, p := range q.Params {
+		if p.Name == "" || handlerParams[p.Name] {
+			return fmt.Errorf("query %q: invalid parameter name %q", q.Name, p.Name)
+		}
+		for _, other := range q.Params[:i] {
+			if other.Name == p.Name {
+				return fmt.Errorf("query %q: duplicate parameter %q", q.Name, p.Name)
+			}
+		}
+		if p.Default != "" {
+			if _, err := p.parse(p.Default); err != nil {
+				return fmt.Errorf("query %q: default of %q: %w", q.Name, p.Name, err)
+			}
+		}
+	}
+
+	h.queries[q.Name] = q
+	return nil
+}
+
+// ServeHTTP - runs the named query and writes the result
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		httpError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	q, ok := h.queries[strings.Trim(r.URL.Path, "/")]
+	if !ok {
+		httpError(w, http.StatusNotFound, "unknown query")
+		return
+	}
+
+	values := r.URL.Query()
+	format, ok := negotiate(values.Get("format"), r.Header.Get("Accept"))
+	if !ok {
+		httpError(w, http.StatusNotAcceptable, "supported formats are json, ndjson and csv")
+		return
+	}
+
+	args, err := q.args(values)
+	if err != nil {
+		httpError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	query := q.SQL
+	var limit, offset int
+	if q.Paginate {
+		if limit, offset, err = h.page(values); err != nil {
+			httpError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		// both are validated integers, so they are safe to format into the query
+		query = fmt.Sprintf("SELECT * FROM (%s) AS godyno_page LIMIT %d OFFSET %d", strings.TrimRight(q.SQL, "; \t\n"), limit, offset)
+	}
+
+	for _, opt := range q.Options {
+		args = append(args, opt)
+	}
+	results, err := QueryToStructContext(r.Context(), h.db, query, args...)
+	if err != nil {
+		if r.Context().Err() != nil {
+			// the client is gone, nobody reads the response
+			return
+		}
+		if h.ErrorLog != nil {
+			h.ErrorLog(r, err)
+		}
+		httpError(w, http.StatusInternalServerError, "query failed")
+		return
+	}
+
+	if q.Paginate && len(results) == limit {
+		next := *r.URL
+		nextValues := r.URL.Query()
+		nextValues.Set("offset", strconv.Itoa(offset+limit))
+		nextValues.Set("limit", strconv.Itoa(limit))
+		next.RawQuery = nextValues.Encode()
+		w.Header().Set("Link", fmt.Sprintf("<%s>; rel=\"next\"", next.RequestURI()))
+	}
+
+	w.Header().Set("Content-Type", handlerFormats[format]+"; charset=utf-8")
+	w.Header().Set("Vary", "Accept")
+	if r.Method == http.MethodHead {
+		return
+	}
+
+	switch format {
+	case "ndjson":
+		err = NewNDJSONEncoder(w).EncodeAll(results)
+	case "csv":
+		err = NewCSVWriter(w).WriteAll(results)
+	default:
+		if results == nil {
+			results = ResultSet{}
+		}
+		err = json.NewEncoder(w).Encode(results)
+	}
+	if err != nil && h.ErrorLog != nil {
+		h.ErrorLog(r, err)
+	}
+}
+
+// args - validates the request parameters and returns the query arguments in order
+func (q NamedQuery) args(values url.Values) ([]any, error) {
+	for name := range values {
+		if handlerParams[name] {
+			continue
+		}
+		if !q.hasParam(name) {
+			return nil, fmt.Errorf("unknown parameter %q", name)
+		}
+	}
+
+	args := make([]any, len(q.Params))
+	for i, p := range q.Params {
+		raw, ok := values[p.Name]
+		switch {
+		case ok && len(raw) > 1:
+			return nil, fmt.Errorf("parameter %q is given more than once", p.Name)
+		case ok:
+			val, err := p.parse(raw[0])
+			if err != nil {
+				return nil, fmt.Errorf("parameter %q: %w", p.Name, err)
+			}
+			args[i] = val
+		case p.Required:
+			return nil, fmt.Errorf("parameter %q is required", p.Name)
+		case p.Default != "":
+			args[i], _ = p.parse(p.Default) // validated by Register
+		}
+	}
+
+	return args, nil
+}
+
+// hasParam - reports whether the query declares a parameter
+func (q NamedQuery) hasParam(name string) bool {
+	for _, p := range q.Params {
+		if p.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// parse - converts a request value to the parameter type
+func (p Param) parse(s string) (any, error) {
+	var val any
+	var err error
+
+	switch p.Type {
+	case ParamInt:
+		val, err = strconv.ParseInt(s, 10, 64)
+	case ParamFloat:
+		val, err = strconv.ParseFloat(s, 64)
+	case ParamBool:
+		val, err = strconv.ParseBool(s)
+	case ParamTime:
+		if val, err = time.Parse(time.RFC3339, s); err != nil {
+			val, err = time.Parse(time.DateOnly, s)
+		}
+	default:
+		return s, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%q is not a valid %s", s, p.Type)
+	}
+	return val, nil
+}
+
+// page - returns the validated limit and offset of a paginated request
+func (h *Handler) page(values url.Values) (limit, offset int, err error) {
+	limit = h.DefaultLimit
+	if s := values.Get("limit"); s != "" {
+		if limit, err = strconv.Atoi(s); err != nil || limit < 1 {
+			return 0, 0, fmt.Errorf("limit must be a positive integer")
+		}
+	}
+	if h.MaxLimit > 0 && limit > h.MaxLimit {
+		return 0, 0, fmt.Errorf("limit must not exceed %d", h.MaxLimit)
+	}
+
+	if s := values.Get("offset"); s != "" {
+		if offset, err = strconv.Atoi(s); err != nil || offset < 0 {
+			return 0, 0, fmt.Errorf("offset must be a non-negative integer")
+		}
+	}
+	return limit, offset, nil
+}
+
+// acceptRanges - the formats each media range of an Accept header covers, in order of preference
+var acceptRanges = map[string][]string{
+	"application/json":     {"json"},
+	"application/x-ndjson": {"ndjson"},
+	"application/ndjson":   {"ndjson"},
+	"text/csv":             {"csv"},
+	"application/*":        {"json", "ndjson"},
+	"text/*":               {"csv"},
+	"*/*":                  {"json", "ndjson", "csv"},
+}
+
+// negotiate - picks the response format from the format parameter or the Accept header.
+// Each format takes the q-value of the most specific media range covering it,
+// so "application/json;q=0, */*" refuses only json. The highest q-value wins,
+// the earlier range on a tie, and a format with q=0 is never picked.
+func negotiate(param, accept string) (string, bool) {
+	if param != "" {
+		_, ok := handlerFormats[param]
+		return param, ok
+	}
+	if accept == "" {
+		return "json", true
+	}
+
+	type choice struct {
+		q           float64
+		specificity int
+		order       int
+	}
+	choices := map[string]choice{}
+	order := 0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if v, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(v, 64); err != nil || q < 0 || q > 1 {
+				continue
+			}
+		}
+
+		// "type/subtype" beats "type/*", which beats "*/*"
+		specificity := 2 - strings.Count(mediaType, "*")
+		for _, format := range acceptRanges[mediaType] {
+			if cur, ok := choices[format]; !ok || specificity > cur.specificity {
+				choices[format] = choice{q, specificity, order}
+			}
+			order++
+		}
+	}
+
+	best, bestFormat := choice{}, ""
+	for format, c := range choices {
+		if c.q > best.q || (c.q == best.q && c.q > 0 && c.order < best.order) {
+			best, bestFormat = c, format
+		}
+	}
+	return bestFormat, bestFormat != ""
+}
+
+// httpError - writes an error response as JSON
+func httpError(w http.ResponseWriter, status int, msg string) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{"error": msg})
+}
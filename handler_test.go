@@ -0,0 +1,210 @@
+package godyno
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// newTestHandler - returns a handler with a paginated products query
+func newTestHandler(t *testing.T) (*Handler, sqlmock.Sqlmock) {
+	t.Helper()
+
+	db, mock := setupMockDB(t)
+	t.Cleanup(func() { db.Close() })
+
+	h := NewHandler(db)
+	h.DefaultLimit = 2
+	h.MaxLimit = 10
+	err := h.Register(NamedQuery{
+		Name: "products",
+		SQL:  "SELECT id, title FROM products WHERE category_id = $1 AND active = $2;",
+		Params: []Param{
+			{Name: "category", Type: ParamInt, Required: true},
+			{Name: "active", Type: ParamBool, Default: "true"},
+		},
+		Paginate: true,
+	})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	return h, mock
+}
+
+func serve(h http.Handler, target, accept string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestHandler(t *testing.T) {
+	const pageQuery = `SELECT \* FROM \(SELECT id, title FROM products WHERE category_id = \$1 AND active = \$2\) AS godyno_page LIMIT 2 OFFSET 0`
+
+	t.Run("json with next page", func(t *testing.T) {
+		h, mock := newTestHandler(t)
+		mock.ExpectQuery(pageQuery).WithArgs(int64(5), true).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "title"}).AddRow(1, "Desk").AddRow(2, "Lamp"))
+
+		rec := serve(h, "/products?category=5", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Status = %d, body %s", rec.Code, rec.Body)
+		}
+		if got := rec.Body.String(); got != `[{"id":1,"title":"Desk"},{"id":2,"title":"Lamp"}]`+"\n" {
+			t.Errorf("Body = %s", got)
+		}
+		if ct := rec.Header().Get("Content-Type"); ct != "application/json; charset=utf-8" {
+			t.Errorf("Content-Type = %s", ct)
+		}
+		if link := rec.Header().Get("Link"); link != `</products?category=5&limit=2&offset=2>; rel="next"` {
+			t.Errorf("Link = %s", link)
+		}
+		if err := mock.ExpectationsWereMet(); err != nil {
+			t.Errorf("Unfulfilled expectations: %s", err)
+		}
+	})
+
+	t.Run("csv and ndjson negotiation", func(t *testing.T) {
+		h, mock := newTestHandler(t)
+		rows := func() *sqlmock.Rows { return sqlmock.NewRows([]string{"id", "title"}).AddRow(3, "Mouse") }
+		mock.ExpectQuery(`LIMIT 5 OFFSET 10`).WithArgs(int64(5), false).WillReturnRows(rows())
+		mock.ExpectQuery(`LIMIT 2 OFFSET 0`).WithArgs(int64(5), true).WillReturnRows(rows())
+
+		rec := serve(h, "/products?category=5&active=false&limit=5&offset=10", "text/html, text/csv;q=0.9")
+		if rec.Body.String() != "id,title\n3,Mouse\n" || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/csv") {
+			t.Errorf("CSV response = %s (%s)", rec.Body, rec.Header().Get("Content-Type"))
+		}
+		if rec.Header().Get("Link") != "" {
+			t.Error("Last page should not link to a next page")
+		}
+
+		rec = serve(h, "/products?category=5&format=ndjson", "text/csv")
+		if rec.Body.String() != `{"id":3,"title":"Mouse"}`+"\n" {
+			t.Errorf("NDJSON response = %s", rec.Body)
+		}
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		h, _ := newTestHandler(t)
+		tests := []struct {
+			target, accept string
+			status         int
+			want           string
+		}{
+			{"/orders", "", http.StatusNotFound, "unknown query"},
+			{"/products", "", http.StatusBadRequest, `parameter \"category\" is required`},
+			{"/products?category=abc", "", http.StatusBadRequest, `\"abc\" is not a valid integer`},
+			{"/products?category=1&category=2", "", http.StatusBadRequest, "more than once"},
+			{"/products?category=1&sort=id", "", http.StatusBadRequest, `unknown parameter \"sort\"`},
+			{"/products?category=1&limit=0", "", http.StatusBadRequest, "limit must be a positive integer"},
+			{"/products?category=1&limit=11", "", http.StatusBadRequest, "limit must not exceed 10"},
+			{"/products?category=1&offset=-1", "", http.StatusBadRequest, "offset must be a non-negative integer"},
+			{"/products?category=1", "application/xml", http.StatusNotAcceptable, "supported formats"},
+			{"/products?category=1", "text/csv;q=0, application/xml", http.StatusNotAcceptable, "supported formats"},
+			{"/products?category=1&format=xml", "", http.StatusNotAcceptable, "supported formats"},
+		}
+		for _, tt := range tests {
+			rec := serve(h, tt.target, tt.accept)
+			if rec.Code != tt.status || !strings.Contains(rec.Body.String(), tt.want) {
+				t.Errorf("GET %s = %d %s, want %d containing %s", tt.target, rec.Code, rec.Body, tt.status, tt.want)
+			}
+		}
+
+		req := httptest.NewRequest(http.MethodPost, "/products?category=1", nil)
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Code != http.StatusMethodNotAllowed {
+			t.Errorf("POST = %d, want 405", rec.Code)
+		}
+	})
+
+	t.Run("query error and cancellation", func(t *testing.T) {
+		h, mock := newTestHandler(t)
+		var logged []error
+		h.ErrorLog = func(r *http.Request, err error) { logged = append(logged, err) }
+
+		mock.ExpectQuery("SELECT").WillReturnError(sqlmock.ErrCancelled)
+		rec := serve(h, "/products?category=1", "")
+		if rec.Code != http.StatusInternalServerError || strings.Contains(rec.Body.String(), "canceling") {
+			t.Errorf("Query error = %d %s", rec.Code, rec.Body)
+		}
+		if len(logged) != 1 {
+			t.Errorf("ErrorLog called %d times, want 1", len(logged))
+		}
+
+		mock.ExpectQuery("SELECT").WillDelayFor(time.Second).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		req := httptest.NewRequest(http.MethodGet, "/products?category=1", nil).WithContext(ctx)
+		rec = httptest.NewRecorder()
+		h.ServeHTTP(rec, req)
+		if rec.Body.Len() != 0 || len(logged) != 1 {
+			t.Errorf("Cancelled request wrote %q and logged %d errors", rec.Body, len(logged))
+		}
+	})
+
+	t.Run("register", func(t *testing.T) {
+		h, _ := newTestHandler(t)
+		for _, q := range []NamedQuery{
+			{Name: "products"},
+			{Name: "a/b"},
+			{Name: "x", Params: []Param{{Name: "limit"}}},
+			{Name: "y", Params: []Param{{Name: "id"}, {Name: "id"}}},
+			{Name: "z", Params: []Param{{Name: "id", Type: ParamInt, Default: "one"}}},
+		} {
+			if err := h.Register(q); err == nil {
+				t.Errorf("Register(%+v) should fail", q)
+			}
+		}
+	})
+}
+
+func TestQueryToStructContext(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := QueryToStructContext(ctx, db, "SELECT id FROM products"); err == nil {
+		t.Error("QueryToStructContext() with a cancelled context should fail")
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		param, accept string
+		want          string
+		ok            bool
+	}{
+		{"", "", "json", true},
+		{"csv", "application/json", "csv", true},
+		{"", "text/csv, application/json", "csv", true},
+		{"", "text/csv;q=0.5, application/json", "json", true},
+		{"", "text/csv;q=0, application/json;q=0.1", "json", true},
+		{"", "application/x-ndjson;q=0.9, text/*;q=0.9", "ndjson", true},
+		{"", "application/json;q=0, */*", "ndjson", true},
+		{"", "application/*;q=0, */*;q=0.5", "csv", true},
+		{"", "*/*;q=0, text/csv", "csv", true},
+		{"", "*/*;q=0", "", false},
+		{"", "application/json;q=0, text/*;q=0.2", "csv", true},
+		{"", "text/csv;q=abc, application/json;q=0.3", "json", true},
+		{"", "text/csv;q=0", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := negotiate(tt.param, tt.accept)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("negotiate(%q, %q) = %q, %t, want %q, %t", tt.param, tt.accept, got, ok, tt.want, tt.ok)
+		}
+	}
+}
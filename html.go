@@ -0,0 +1,100 @@
+package godyno
+
+import (
+	"bufio"
+	"html"
+	"html/template"
+	"io"
+	"strings"
+)
+
+// HTMLWriter - renders results as an HTML table. Columns are taken from the
+// first row and every header and cell is escaped. Numeric cells have the
+// class "number" and NULL cells the class "null" for styling.
+type HTMLWriter struct {
+	// Class is the class attribute of the table element, omitted when empty
+	Class string
+	// Null is written for NULL values, "" by default
+	Null string
+	// TimeFormat is the layout for time.Time values, time.RFC3339 by default
+	TimeFormat string
+
+	out io.Writer
+}
+
+// NewHTMLWriter - returns an HTMLWriter writing to w
+func NewHTMLWriter(w io.Writer) *HTMLWriter {
+	return &HTMLWriter{out: w}
+}
+
+// HTML - renders the result set as an HTML table, ready to be used in html/template
+func (rs ResultSet) HTML() template.HTML {
+	var b strings.Builder
+	_ = NewHTMLWriter(&b).WriteAll(rs)
+	return template.HTML(b.String())
+}
+
+// WriteAll - renders every row as one table; an empty set renders an empty table
+func (hw *HTMLWriter) WriteAll(rs ResultSet) error {
+	var fields []Field
+	if len(rs) > 0 {
+		fields = rs[0].Fields()
+	}
+
+	w := bufio.NewWriter(hw.out)
+	w.WriteString("<table")
+	if hw.Class != "" {
+		w.WriteString(` class="` + html.EscapeString(hw.Class) + `"`)
+	}
+	w.WriteString(">\n<thead>\n<tr>")
+	for _, field := range fields {
+		w.WriteString("<th>" + html.EscapeString(field.Column) + "</th>")
+	}
+	w.WriteString("</tr>\n</thead>\n<tbody>\n")
+
+	for _, dr := range rs {
+		w.WriteString("<tr>")
+		for _, field := range fields {
+			switch {
+			case dr.IsNull(field.Column) || !dr.Has(field.Column):
+				w.WriteString(`<td class="null">` + html.EscapeString(hw.Null) + "</td>")
+			case isNumeric(field.Type):
+				w.WriteString(`<td class="number">` + html.EscapeString(formatValue(dr.Get(field.Column), hw.TimeFormat)) + "</td>")
+			default:
+				w.WriteString("<td>" + html.EscapeString(formatValue(dr.Get(field.Column), hw.TimeFormat)) + "</td>")
+			}
+		}
+		w.WriteString("</tr>\n")
+	}
+	w.WriteString("</tbody>\n</table>\n")
+
+	return w.Flush()
+}
+
+// FuncMap - returns template functions for working with results. The map can be
+// passed to both html/template and text/template:
+//
+//	tmpl := template.New("page").Funcs(godyno.FuncMap())
+//
+// Available functions: get, getString, getInt, getFloat, getBool, isNull, has,
+// fields (the Field list of a result), format (text of a field, times with an
+// optional layout) and table (a result set as an HTML table).
+func FuncMap() map[string]any {
+	return map[string]any{
+		"get":       func(dr *DBResult, field string) any { return dr.Get(field) },
+		"getString": func(dr *DBResult, field string) string { return dr.GetString(field) },
+		"getInt":    func(dr *DBResult, field string) int { return dr.GetInt(field) },
+		"getFloat":  func(dr *DBResult, field string) float64 { return dr.GetFloat(field) },
+		"getBool":   func(dr *DBResult, field string) bool { return dr.GetBool(field) },
+		"isNull":    func(dr *DBResult, field string) bool { return dr.IsNull(field) },
+		"has":       func(dr *DBResult, field string) bool { return dr.Has(field) },
+		"fields":    func(dr *DBResult) []Field { return dr.Fields() },
+		"format": func(dr *DBResult, field string, layout ...string) string {
+			if dr.IsNull(field) || !dr.Has(field) {
+				return ""
+			}
+			return formatValue(dr.Get(field), strings.Join(layout, ""))
+		},
+		"table": func(rs ResultSet) template.HTML { return rs.HTML() },
+	}
+}
@@ -0,0 +1,74 @@
+package godyno
+
+import (
+	htmltemplate "html/template"
+	"strings"
+	"testing"
+	texttemplate "text/template"
+)
+
+func TestHTMLWriter(t *testing.T) {
+	rs := newTestSet(t,
+		map[string]any{"id": int64(1), "title": `<b>"Desk"</b>`, "address.city": "Izmir"},
+		map[string]any{"id": int64(2), "title": "Lamp & Co", "address.city": nil},
+	)
+
+	var b strings.Builder
+	w := NewHTMLWriter(&b)
+	w.Class = "results"
+	w.Null = "-"
+	if err := w.WriteAll(rs); err != nil {
+		t.Fatalf("WriteAll() error = %v", err)
+	}
+
+	want := `<table class="results">
+<thead>
+<tr><th>address.city</th><th>id</th><th>title</th></tr>
+</thead>
+<tbody>
+<tr><td>Izmir</td><td class="number">1</td><td>&lt;b&gt;&#34;Desk&#34;&lt;/b&gt;</td></tr>
+<tr><td class="null">-</td><td class="number">2</td><td>Lamp &amp; Co</td></tr>
+</tbody>
+</table>
+`
+	if b.String() != want {
+		t.Errorf("WriteAll() =\n%s\nwant\n%s", b.String(), want)
+	}
+}
+
+func TestFuncMap(t *testing.T) {
+	rs := newTestSet(t,
+		map[string]any{"id": int64(1), "title": "<Desk>", "price": 199.5, "active": true, "address.city": nil},
+	)
+
+	const page = `{{range .}}{{getInt . "id"}}:{{getString . "title"}}:{{getFloat . "price"}}:` +
+		`{{if getBool . "active"}}active{{end}}:{{if isNull . "address.city"}}null{{end}}:` +
+		`{{range fields .}}{{.Column}},{{end}}{{end}}`
+
+	t.Run("html/template", func(t *testing.T) {
+		tmpl := htmltemplate.Must(htmltemplate.New("page").Funcs(FuncMap()).Parse(page + `|{{table .}}`))
+		var b strings.Builder
+		if err := tmpl.Execute(&b, rs); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+
+		got := b.String()
+		if !strings.HasPrefix(got, "1:&lt;Desk&gt;:199.5:active:null:active,address.city,id,price,title,|") {
+			t.Errorf("Execute() = %s", got)
+		}
+		if !strings.Contains(got, "<table>") || !strings.Contains(got, "<td>&lt;Desk&gt;</td>") {
+			t.Errorf("table should render unescaped HTML with escaped cells: %s", got)
+		}
+	})
+
+	t.Run("text/template", func(t *testing.T) {
+		tmpl := texttemplate.Must(texttemplate.New("page").Funcs(FuncMap()).Parse(page))
+		var b strings.Builder
+		if err := tmpl.Execute(&b, rs); err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if got := b.String(); got != "1:<Desk>:199.5:active:null:active,address.city,id,price,title," {
+			t.Errorf("Execute() = %s", got)
+		}
+	})
+}
@@ -0,0 +1,265 @@
+package godyno
+
+import (
+	"fmt"
+	"reflect"
+	"unicode/utf8"
+)
+
+// Metadata - describes how the columns of a query result were typed
+type Metadata struct {
+	Columns     []ColumnMeta
+	SampledRows int
+}
+
+// ColumnMeta - type inference details of a single column.
+// Nullable is true when the driver reports the column as nullable or a
+// NULL was seen while sampling.
+// Confidence is the share of sampled non-NULL values whose own narrowest
+// type is the chosen type; 1 means every sampled value agreed and 0 means
+// no value was seen (e.g. only NULLs).
+type ColumnMeta struct {
+	Name         string
+	DatabaseType string
+	Type         reflect.Type
+	Nullable     bool
+	Sampled      int
+	Nulls        int
+	Confidence   float64
+}
+
+// Column - returns the metadata of a column by its original name
+func (m *Metadata) Column(name string) (ColumnMeta, bool) {
+	if m == nil {
+		return ColumnMeta{}, false
+	}
+	for _, col := range m.Columns {
+		if col.Name == name {
+			return col, true
+		}
+	}
+	return ColumnMeta{}, false
+}
+
+// Candidate types a textual value can be parsed as
+const (
+	maybeBool = 1 << iota
+	maybeInt
+	maybeFloat
+
+	maybeAll = maybeBool | maybeInt | maybeFloat
+)
+
+var (
+	stringType  = reflect.TypeOf("")
+	intType     = reflect.TypeOf(int(0))
+	int64Type   = reflect.TypeOf(int64(0))
+	float64Type = reflect.TypeOf(float64(0))
+	boolType    = reflect.TypeOf(false)
+	bytesType   = reflect.TypeOf([]byte(nil))
+)
+
+// inferType - determines the narrowest Go type of a single raw driver value
+func inferType(val any, cfg *config) reflect.Type {
+	switch v := val.(type) {
+	case []byte:
+		// Binary data that is not valid text stays a byte slice
+		if !utf8.Valid(v) {
+			return bytesType
+		}
+		// Byte array, possible types: string, int, float, bool
+		return pickType(textCandidates(string(v), cfg), cfg)
+	case nil:
+		return stringType
+	default:
+		return reflect.TypeOf(val)
+	}
+}
+
+// textCandidates - returns the types a textual value can be parsed as
+func textCandidates(str string, cfg *config) int {
+	if cfg.strictStrings {
+		return 0
+	}
+
+	mask := 0
+	if _, ok := cfg.parseBool(str); ok {
+		mask |= maybeBool
+	}
+	if _, ok := cfg.parseInt(str); ok {
+		mask |= maybeInt
+	}
+	if _, ok := cfg.parseFloat(str); ok {
+		mask |= maybeFloat
+	}
+	return mask
+}
+
+// pickType - chooses the preferred type among the candidates.
+// Numbers win over booleans unless a custom vocabulary is set, so "1"/"0"
+// become booleans only when asked for.
+func pickType(mask int, cfg *config) reflect.Type {
+	if cfg.hasBoolVocabulary() && mask&maybeBool != 0 {
+		return boolType
+	}
+
+	switch {
+	case mask&maybeInt != 0:
+		return intType
+	case mask&maybeFloat != 0:
+		return float64Type
+	case mask&maybeBool != 0:
+		return boolType
+	}
+	return stringType
+}
+
+// columnSample - collects the evidence for the type of one column
+type columnSample struct {
+	mask    int
+	text    bool
+	binary  bool
+	native  reflect.Type
+	counts  map[reflect.Type]int
+	sampled int
+	nulls   int
+}
+
+// newColumnSample - returns an empty sample accepting every candidate type
+func newColumnSample() *columnSample {
+	return &columnSample{
+		mask:   maybeAll,
+		counts: make(map[reflect.Type]int),
+	}
+}
+
+// add - records a sampled value
+func (s *columnSample) add(val any, cfg *config) {
+	if val == nil {
+		s.nulls++
+		return
+	}
+
+	s.sampled++
+	s.counts[inferType(val, cfg)]++
+
+	if b, ok := val.([]byte); ok {
+		s.text = true
+		s.binary = s.binary || !utf8.Valid(b)
+		s.mask &= textCandidates(string(b), cfg)
+		return
+	}
+	s.native = widen(s.native, reflect.TypeOf(val))
+}
+
+// resolve - returns the narrowest type compatible with every sampled value
+func (s *columnSample) resolve(cfg *config) reflect.Type {
+	if s.binary {
+		return bytesType
+	}
+
+	var typ reflect.Type
+	if s.text {
+		typ = pickType(s.mask, cfg)
+	}
+	typ = widen(typ, s.native)
+
+	if typ == nil {
+		// Only NULLs were seen
+		return stringType
+	}
+	return typ
+}
+
+// confidence - returns the share of sampled values whose own type is typ
+func (s *columnSample) confidence(typ reflect.Type) float64 {
+	if s.sampled == 0 {
+		return 0
+	}
+	return float64(s.counts[typ]) / float64(s.sampled)
+}
+
+// inferColumns - sets the type of every column from the sampled rows
+func inferColumns(cols []column, sample [][]any, cfg *config) *Metadata {
+	meta := &Metadata{
+		Columns:     make([]ColumnMeta, len(cols)),
+		SampledRows: len(sample),
+	}
+
+	for i := range cols {
+		s := newColumnSample()
+		for _, values := range sample {
+			s.add(values[i], cfg)
+		}
+
+		confidence := 1.0
+		if cols[i].conv == nil && cols[i].isBinary() {
+			// Binary data must not be sniffed or turned into a string
+			cols[i].typ = bytesType
+		} else if cols[i].conv == nil {
+			cols[i].typ = s.resolve(cfg)
+			confidence = s.confidence(cols[i].typ)
+		}
+		if s.sampled == 0 {
+			confidence = 0
+		}
+
+		meta.Columns[i] = ColumnMeta{
+			Name:         cols[i].name,
+			DatabaseType: cols[i].dbType,
+			Type:         cols[i].typ,
+			Nullable:     cols[i].nullable || s.nulls > 0,
+			Sampled:      s.sampled,
+			Nulls:        s.nulls,
+			Confidence:   confidence,
+		}
+	}
+
+	return meta
+}
+
+// widen - returns the narrowest type that can hold values of both types
+func widen(a, b reflect.Type) reflect.Type {
+	switch {
+	case a == nil:
+		return b
+	case b == nil || a == b:
+		return a
+	case isNumeric(a) && isNumeric(b):
+		if isFloat(a) || isFloat(b) {
+			return float64Type
+		}
+		return int64Type
+	}
+	return stringType
+}
+
+// coerce - converts a value to typ, widening numbers and formatting strings
+func coerce(val any, typ reflect.Type) (any, error) {
+	v := reflect.ValueOf(val)
+	switch {
+	case v.Type().AssignableTo(typ):
+		return val, nil
+	case isNumeric(v.Type()) && isNumeric(typ):
+		return v.Convert(typ).Interface(), nil
+	case typ.Kind() == reflect.String:
+		return reflect.ValueOf(fmt.Sprint(val)).Convert(typ).Interface(), nil
+	}
+	return nil, fmt.Errorf("cannot use %T as %s", val, typ)
+}
+
+// isNumeric - reports whether the type is an integer or float kind
+func isNumeric(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// isFloat - reports whether the type is a float kind
+func isFloat(t reflect.Type) bool {
+	return t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64
+}
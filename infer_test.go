@@ -0,0 +1,113 @@
+package godyno
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSampleRows(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	newRows := func() *sqlmock.Rows {
+		return sqlmock.NewRows([]string{"id", "price", "flag", "note"}).
+			AddRow([]byte("1"), []byte("10"), []byte("1"), nil).
+			AddRow([]byte("2"), []byte("10.5"), []byte("true"), []byte("x")).
+			AddRow([]byte("3"), []byte("11"), []byte("0"), nil)
+	}
+
+	t.Run("First row only fails on later rows", func(t *testing.T) {
+		mock.ExpectQuery("SELECT").WillReturnRows(newRows())
+
+		if _, err := QueryToStruct(db, "SELECT * FROM t"); err == nil {
+			t.Error("Expected a conversion error but got none")
+		}
+	})
+
+	t.Run("Sampling every row", func(t *testing.T) {
+		mock.ExpectQuery("SELECT").WillReturnRows(newRows())
+
+		results, err := QueryToStruct(db, "SELECT * FROM t", WithSampleRows(0))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if len(results) != 3 {
+			t.Fatalf("Expected 3 results, got %d", len(results))
+		}
+
+		if got := results[1].Get("price"); got != 10.5 {
+			t.Errorf("Get(price) = %#v, want 10.5", got)
+		}
+		if got := results[0].Get("price"); got != 10.0 {
+			t.Errorf("Get(price) = %#v, want float 10", got)
+		}
+		if got := results[1].Get("flag"); got != true {
+			t.Errorf("Get(flag) = %#v, want true", got)
+		}
+		if got := results[1].Get("note"); got != "x" {
+			t.Errorf("Get(note) = %#v, want x", got)
+		}
+
+		meta := results[0].Metadata()
+		if meta == nil || meta.SampledRows != 3 {
+			t.Fatalf("Metadata() = %+v, want 3 sampled rows", meta)
+		}
+
+		price, _ := meta.Column("price")
+		if price.Type != reflect.TypeOf(float64(0)) || price.Confidence != 1.0/3 {
+			t.Errorf("price meta = %+v, want float64 with confidence 1/3", price)
+		}
+		id, _ := meta.Column("id")
+		if id.Type != reflect.TypeOf(0) || id.Confidence != 1 {
+			t.Errorf("id meta = %+v, want int with confidence 1", id)
+		}
+		note, _ := meta.Column("note")
+		if note.Sampled != 1 || note.Nulls != 2 {
+			t.Errorf("note meta = %+v, want 1 sampled and 2 nulls", note)
+		}
+	})
+
+	t.Run("Partial sample", func(t *testing.T) {
+		mock.ExpectQuery("SELECT").WillReturnRows(
+			sqlmock.NewRows([]string{"id"}).
+				AddRow(nil).
+				AddRow([]byte("7")).
+				AddRow([]byte("8")),
+		)
+
+		results, err := QueryToStruct(db, "SELECT id FROM t", WithSampleRows(2))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if got := results[2].Get("id"); got != 8 {
+			t.Errorf("Get(id) = %#v, want 8", got)
+		}
+		if got := results[0].Get("id"); got != 0 {
+			t.Errorf("Get(id) for NULL = %#v, want 0", got)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestWiden(t *testing.T) {
+	testCases := []struct {
+		a, b, want reflect.Type
+	}{
+		{nil, intType, intType},
+		{intType, intType, intType},
+		{intType, int64Type, int64Type},
+		{int64Type, float64Type, float64Type},
+		{boolType, intType, stringType},
+	}
+
+	for _, tc := range testCases {
+		if got := widen(tc.a, tc.b); got != tc.want {
+			t.Errorf("widen(%v, %v) = %v, want %v", tc.a, tc.b, got, tc.want)
+		}
+	}
+}
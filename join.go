@@ -0,0 +1,149 @@
+package godyno
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// JoinKind - decides what happens to rows without a match in Join
+type JoinKind int
+
+const (
+	// InnerJoin keeps only matching rows
+	InnerJoin JoinKind = iota
+	// LeftJoin keeps every left row; missing right fields are NULL
+	LeftJoin
+	// FullJoin keeps every row of both sides; missing fields are NULL
+	FullJoin
+)
+
+// Join - merges the rows of two result sets where leftKey equals rightKey.
+// By default the right fields are added next to the left ones and a right
+// field whose name is already taken gets a "right_" prefix (and a numeric
+// suffix if that is taken too, e.g. "right_id_2"); with
+// WithJoinPrefix they are nested under the prefix instead (e.g.
+// "customer.name"). NULL keys never match and numbers match across types.
+func Join(left, right ResultSet, leftKey, rightKey string, kind JoinKind, opts ...Option) (ResultSet, error) {
+	cfg, _ := newConfig(optionArgs(opts))
+
+	leftTemplate := templateEntries(left)
+	rightTemplate := templateEntries(right)
+
+	// Name the right fields; a renamed field must not clash with any field either
+	leftNames := make(map[string]bool, len(leftTemplate))
+	taken := make(map[string]bool, len(leftTemplate)+len(rightTemplate))
+	for _, e := range leftTemplate {
+		leftNames[e.column] = true
+		taken[e.column] = true
+	}
+	for _, e := range rightTemplate {
+		taken[e.column] = true
+	}
+	rightNames := make(map[string]string, len(rightTemplate))
+	for i, e := range rightTemplate {
+		name := e.column
+		switch {
+		case cfg.joinPrefix != "":
+			name = cfg.joinPrefix + "." + name
+		case leftNames[name]:
+			base := "right_" + name
+			name = base
+			for n := 2; taken[name]; n++ {
+				name = fmt.Sprintf("%s_%d", base, n)
+			}
+			taken[name] = true
+		}
+		rightNames[e.column] = name
+		rightTemplate[i].column = name
+	}
+
+	// Index the right rows by key
+	index := make(map[string][]int)
+	for i, dr := range right {
+		if key, ok := joinKey(dr, rightKey); ok {
+			index[key] = append(index[key], i)
+		}
+	}
+
+	var rows [][]entry
+	matched := make([]bool, len(right))
+
+	for _, l := range left {
+		var matches []int
+		if key, ok := joinKey(l, leftKey); ok {
+			matches = index[key]
+		}
+
+		if len(matches) == 0 {
+			if kind != InnerJoin {
+				rows = append(rows, append(l.entries(), rightTemplate...))
+			}
+			continue
+		}
+
+		for _, i := range matches {
+			matched[i] = true
+			rows = append(rows, append(l.entries(), renamed(right[i].entries(), rightNames)...))
+		}
+	}
+
+	if kind == FullJoin {
+		for i, r := range right {
+			if !matched[i] {
+				rows = append(rows, append(append([]entry{}, leftTemplate...), renamed(r.entries(), rightNames)...))
+			}
+		}
+	}
+
+	return buildSet(rows)
+}
+
+// templateEntries - returns the fields of the first row with NULL values
+func templateEntries(rs ResultSet) []entry {
+	first := rs.First()
+	if first == nil {
+		return nil
+	}
+
+	entries := first.entries()
+	for i := range entries {
+		entries[i].value = nil
+	}
+	return entries
+}
+
+// renamed - returns the entries with their columns renamed
+func renamed(entries []entry, names map[string]string) []entry {
+	for i, e := range entries {
+		if name, ok := names[e.column]; ok {
+			entries[i].column = name
+		}
+	}
+	return entries
+}
+
+// joinKey - returns the lookup key of a row; ok is false for NULL keys
+func joinKey(dr *DBResult, field string) (string, bool) {
+	val := dr.Get(field)
+	if val == nil || dr.IsNull(field) {
+		return "", false
+	}
+
+	// Numbers match across types; integers keep all their digits so that
+	// IDs above 2^53 don't collide, floats only match integers they equal
+	switch v := reflect.ValueOf(val); {
+	case v.CanUint():
+		return "n:" + strconv.FormatUint(v.Uint(), 10), true
+	case v.CanInt():
+		return "n:" + strconv.FormatInt(v.Int(), 10), true
+	case v.CanFloat():
+		f := v.Float()
+		if f == math.Trunc(f) && f >= math.MinInt64 && f < math.MaxInt64 {
+			return "n:" + strconv.FormatInt(int64(f), 10), true
+		}
+		return "n:" + strconv.FormatFloat(f, 'g', -1, 64), true
+	}
+	return fmt.Sprintf("%T:%v", val, val), true
+}
@@ -0,0 +1,120 @@
+package godyno
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJoin(t *testing.T) {
+	orders := newTestSet(t,
+		map[string]any{"id": int64(1), "customer_id": int64(10), "total": 50.0},
+		map[string]any{"id": int64(2), "customer_id": int64(20), "total": 20.0},
+		map[string]any{"id": int64(3), "customer_id": int64(10), "total": 5.0},
+	)
+	customers := newTestSet(t,
+		map[string]any{"id": 10, "name": "Ayse"},
+		map[string]any{"id": 30, "name": "Mehmet"},
+	)
+
+	testCases := []struct {
+		name string
+		kind JoinKind
+		opts []Option
+		want string
+	}{
+		{
+			name: "Inner join flattened",
+			kind: InnerJoin,
+			want: `[{"customer_id":10,"id":1,"total":50,"right_id":10,"name":"Ayse"},` +
+				`{"customer_id":10,"id":3,"total":5,"right_id":10,"name":"Ayse"}]`,
+		},
+		{
+			name: "Left join with prefix",
+			kind: LeftJoin,
+			opts: []Option{WithJoinPrefix("customer")},
+			want: `[{"customer_id":10,"id":1,"total":50,"customer":{"id":10,"name":"Ayse"}},` +
+				`{"customer_id":20,"id":2,"total":20,"customer":{"id":null,"name":null}},` +
+				`{"customer_id":10,"id":3,"total":5,"customer":{"id":10,"name":"Ayse"}}]`,
+		},
+		{
+			name: "Full join",
+			kind: FullJoin,
+			opts: []Option{WithJoinPrefix("customer")},
+			want: `[{"customer_id":10,"id":1,"total":50,"customer":{"id":10,"name":"Ayse"}},` +
+				`{"customer_id":20,"id":2,"total":20,"customer":{"id":null,"name":null}},` +
+				`{"customer_id":10,"id":3,"total":5,"customer":{"id":10,"name":"Ayse"}},` +
+				`{"customer_id":null,"id":null,"total":null,"customer":{"id":30,"name":"Mehmet"}}]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			joined, err := Join(orders, customers, "customer_id", "id", tc.kind, tc.opts...)
+			if err != nil {
+				t.Fatalf("Join() error = %v", err)
+			}
+
+			got, _ := json.Marshal(joined)
+			if string(got) != tc.want {
+				t.Errorf("Join() =\n%s\nwant\n%s", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestJoinEmptyRight(t *testing.T) {
+	orders := newTestSet(t, map[string]any{"id": int64(1)})
+
+	joined, err := Join(orders, nil, "id", "order_id", LeftJoin)
+	if err != nil {
+		t.Fatalf("Join() error = %v", err)
+	}
+	if len(joined) != 1 || joined[0].Len() != 1 {
+		t.Errorf("Join() = %v", joined)
+	}
+}
+
+func TestJoinTakenRightName(t *testing.T) {
+	orders := newTestSet(t, map[string]any{"id": int64(1), "right_id": int64(2)})
+	items := newTestSet(t, map[string]any{"id": int64(1), "right_id": int64(3), "right_id_2": int64(4)})
+
+	joined, err := Join(orders, items, "id", "id", InnerJoin)
+	if err != nil {
+		t.Fatalf("Join() error = %v", err)
+	}
+
+	got, _ := json.Marshal(joined)
+	want := `[{"id":1,"right_id":2,"right_id_3":1,"right_right_id":3,"right_id_2":4}]`
+	if string(got) != want {
+		t.Errorf("Join() = %s, want %s", got, want)
+	}
+}
+
+func TestJoinLargeIntegerKeys(t *testing.T) {
+	left := newTestSet(t,
+		map[string]any{"id": int64(9007199254740993)},
+		map[string]any{"id": int64(7)},
+	)
+
+	right := newTestSet(t,
+		map[string]any{"uid": int64(9007199254740992), "name": "big"},
+		map[string]any{"uid": int64(7), "name": "seven"},
+	)
+	joined, err := Join(left, right, "id", "uid", InnerJoin)
+	if err != nil {
+		t.Fatalf("Join() error = %v", err)
+	}
+	if got := joined.Pluck("name"); len(got) != 1 || got[0] != "seven" {
+		t.Errorf("Join() = %v, want only id 7 to match", got)
+	}
+
+	// Floats still match integers they equal
+	floats := newTestSet(t, map[string]any{"uid": 7.0, "name": "float"}, map[string]any{"uid": 7.5, "name": "half"})
+	joined, err = Join(left, floats, "id", "uid", InnerJoin)
+	if err != nil {
+		t.Fatalf("Join() error = %v", err)
+	}
+	if got := joined.Pluck("name"); len(got) != 1 || got[0] != "float" {
+		t.Errorf("Join() with float keys = %v, want [float]", got)
+	}
+}
@@ -0,0 +1,82 @@
+package godyno
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+)
+
+// MarshalJSON - encodes the result as a JSON object in column order.
+// NULL columns are encoded as null and []byte values as base64 strings.
+func (dr *DBResult) MarshalJSON() (data []byte, err error) {
+	defer recoverError(&err, ErrReflection, "")
+
+	if dr == nil {
+		return []byte("null"), nil
+	}
+
+	var buf bytes.Buffer
+	if err := dr.writeJSON(&buf, reflect.ValueOf(dr.value), ""); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeJSON - writes one (possibly nested) struct level as a JSON object
+func (dr *DBResult) writeJSON(buf *bytes.Buffer, v reflect.Value, prefix string) error {
+	if !v.IsValid() {
+		buf.WriteString("{}")
+		return nil
+	}
+
+	buf.WriteByte('{')
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		name := fieldKey(sf)
+		path := prefix + name
+
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(name)
+		buf.Write(key)
+		buf.WriteByte(':')
+
+		if isNested(sf.Type) {
+			if err := dr.writeJSON(buf, v.Field(i), path+"."); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if dr.IsNull(path) {
+			buf.WriteString("null")
+			continue
+		}
+
+		b, err := json.Marshal(v.Field(i).Interface())
+		if err != nil {
+			return err
+		}
+		buf.Write(b)
+	}
+	buf.WriteByte('}')
+
+	return nil
+}
+
+// fieldKey - returns the original column name of a struct field
+func fieldKey(sf reflect.StructField) string {
+	if tag := sf.Tag.Get("json"); tag != "" {
+		if name, _, _ := strings.Cut(tag, ","); name != "" {
+			return name
+		}
+	}
+	return sf.Name
+}
+
+// isNested - reports whether a field holds a nested dynamic struct (e.g. address in address.city)
+func isNested(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && t.Name() == ""
+}
@@ -0,0 +1,42 @@
+package godyno
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestMarshalJSON(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	columns := []string{"id", "title", "data", "address.city", "address.zip"}
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows(columns).
+		AddRow(int64(1), "Product 1", []byte{0xff, 0x01}, "Izmir", nil))
+
+	results, err := QueryToStruct(db, "SELECT * FROM products", WithStrictStrings())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	got, err := json.Marshal(results[0])
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	want := `{"id":1,"title":"Product 1","data":"/wE=","address":{"city":"Izmir","zip":null}}`
+	if string(got) != want {
+		t.Errorf("json.Marshal() = %s, want %s", got, want)
+	}
+}
+
+func TestMarshalJSONEmpty(t *testing.T) {
+	got, err := json.Marshal(New())
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if string(got) != "{}" {
+		t.Errorf("json.Marshal(New()) = %s, want {}", got)
+	}
+}
@@ -0,0 +1,83 @@
+package godyno
+
+import (
+	"reflect"
+	"sort"
+)
+
+// ToMap - converts the result to a map following the dotted column names,
+// e.g. {"address": {"city": "Izmir"}}. With WithFlatKeys the keys are the
+// column names themselves, e.g. {"address.city": "Izmir"}. NULL columns
+// become nil.
+func (dr *DBResult) ToMap(opts ...Option) map[string]any {
+	cfg, _ := newConfig(optionArgs(opts))
+	m := make(map[string]any)
+
+	for _, f := range dr.Fields() {
+		var value any
+		if !dr.IsNull(f.Column) {
+			value = dr.Get(f.Column)
+		}
+
+		if cfg.flatKeys {
+			m[f.Column] = value
+			continue
+		}
+
+		// Walk down, creating the nested maps
+		level := m
+		for _, part := range f.Path[:len(f.Path)-1] {
+			next, ok := level[part].(map[string]any)
+			if !ok {
+				next = make(map[string]any)
+				level[part] = next
+			}
+			level = next
+		}
+		level[f.Path[len(f.Path)-1]] = value
+	}
+
+	return m
+}
+
+// FromMap - builds a DBResult from a map; field types are taken from the values.
+// Nested maps and dotted keys both become nested fields, keys are ordered
+// alphabetically and nil values become NULL string fields.
+func FromMap(m map[string]any) (*DBResult, error) {
+	var entries []entry
+	flattenMap(m, "", &entries)
+
+	return buildResult(entries)
+}
+
+// flattenMap - appends the leaf values of a (nested) map as entries in key order
+func flattenMap(m map[string]any, prefix string, entries *[]entry) {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		value := m[key]
+		if nested, ok := value.(map[string]any); ok {
+			flattenMap(nested, prefix+key+".", entries)
+			continue
+		}
+
+		typ := stringType
+		if value != nil {
+			typ = reflect.TypeOf(value)
+		}
+		*entries = append(*entries, entry{column: prefix + key, typ: typ, value: value})
+	}
+}
+
+// optionArgs - converts options to arguments accepted by newConfig
+func optionArgs(opts []Option) []any {
+	args := make([]any, len(opts))
+	for i, opt := range opts {
+		args[i] = opt
+	}
+	return args
+}
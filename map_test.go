@@ -0,0 +1,100 @@
+package godyno
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestToMap(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "title", "address.city", "address.zip"}).
+			AddRow(1, "Product 1", "Izmir", nil),
+	)
+	results, err := QueryToStruct(db, "SELECT * FROM products")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	nested := results[0].ToMap()
+	want := map[string]any{
+		"id":    int64(1),
+		"title": "Product 1",
+		"address": map[string]any{
+			"city": "Izmir",
+			"zip":  nil,
+		},
+	}
+	if !reflect.DeepEqual(nested, want) {
+		t.Errorf("ToMap() = %#v, want %#v", nested, want)
+	}
+
+	flat := results[0].ToMap(WithFlatKeys())
+	wantFlat := map[string]any{
+		"id":           int64(1),
+		"title":        "Product 1",
+		"address.city": "Izmir",
+		"address.zip":  nil,
+	}
+	if !reflect.DeepEqual(flat, wantFlat) {
+		t.Errorf("ToMap(WithFlatKeys()) = %#v, want %#v", flat, wantFlat)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestFromMap(t *testing.T) {
+	result, err := FromMap(map[string]any{
+		"id":    1,
+		"price": 9.5,
+		"address": map[string]any{
+			"city": "Izmir",
+		},
+		"address.zip": "35000",
+		"note":        nil,
+	})
+	if err != nil {
+		t.Fatalf("FromMap() error = %v", err)
+	}
+
+	if result.GetInt("id") != 1 || result.GetFloat("price") != 9.5 {
+		t.Errorf("Unexpected values: %v", result.ToMap())
+	}
+	if result.GetString("address.city") != "Izmir" || result.GetString("address.zip") != "35000" {
+		t.Errorf("Unexpected nested values: %v", result.ToMap())
+	}
+	if !result.IsNull("note") {
+		t.Error("IsNull(note) = false, want true")
+	}
+
+	var columns []string
+	for _, f := range result.Fields() {
+		columns = append(columns, f.Column)
+	}
+	want := []string{"address.city", "address.zip", "id", "note", "price"}
+	if !reflect.DeepEqual(columns, want) {
+		t.Errorf("Fields() columns = %v, want %v", columns, want)
+	}
+
+	// Round trip
+	if !reflect.DeepEqual(result.ToMap(WithFlatKeys())["address.zip"], "35000") {
+		t.Errorf("ToMap() round trip failed: %v", result.ToMap(WithFlatKeys()))
+	}
+}
+
+func TestFromMapInvalid(t *testing.T) {
+	_, err := FromMap(map[string]any{
+		"address":      "Izmir",
+		"address.city": "Izmir",
+	})
+	if !errors.Is(err, ErrInvalidColumnName) {
+		t.Errorf("FromMap() error = %v, want ErrInvalidColumnName", err)
+	}
+}
@@ -0,0 +1,192 @@
+package godyno
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// entry - a leaf field with its value, used to (re)build a DBResult
+type entry struct {
+	column string
+	typ    reflect.Type
+	value  any
+}
+
+// Set - sets the value of a field, adding the field if it doesn't exist.
+// Nested paths (e.g. "address.country") follow the Get conventions. A value
+// of another numeric type widens the field (e.g. int to float64); other
+// type changes fail with ErrTypeMismatch. A nil value stores NULL.
+func (dr *DBResult) Set(fieldName string, value any) (err error) {
+	defer recoverError(&err, ErrReflection, fieldName)
+
+	entries := dr.entries()
+	found := false
+
+	for i, e := range entries {
+		if e.column != fieldName {
+			continue
+		}
+		found = true
+
+		if value == nil {
+			entries[i].value = nil
+			break
+		}
+
+		typ, err := settableType(e.typ, reflect.TypeOf(value))
+		if err != nil {
+			return newError(ErrTypeMismatch, fieldName, err)
+		}
+		entries[i].typ = typ
+		entries[i].value = value
+		break
+	}
+
+	if !found {
+		if dr.Has(fieldName) {
+			return newError(ErrTypeMismatch, fieldName, fmt.Errorf("cannot replace a nested struct"))
+		}
+
+		typ := stringType
+		if value != nil {
+			typ = reflect.TypeOf(value)
+		}
+		entries = append(entries, entry{column: fieldName, typ: typ, value: value})
+	}
+
+	return dr.rebuild(entries, func(raw map[string]any) {
+		raw[fieldName] = value
+	})
+}
+
+// Unset - removes a field; removing a parent (e.g. "address") removes all of its nested fields
+func (dr *DBResult) Unset(fieldName string) (err error) {
+	defer recoverError(&err, ErrReflection, fieldName)
+
+	if !dr.Has(fieldName) {
+		return newError(ErrFieldNotFound, fieldName, nil)
+	}
+
+	var kept []entry
+	for _, e := range dr.entries() {
+		if !isWithin(e.column, fieldName) {
+			kept = append(kept, e)
+		}
+	}
+
+	return dr.rebuild(kept, func(raw map[string]any) {
+		for column := range raw {
+			if isWithin(column, fieldName) {
+				delete(raw, column)
+			}
+		}
+	})
+}
+
+// settableType - returns the field type after storing a value of type value in a field of type field
+func settableType(field, value reflect.Type) (reflect.Type, error) {
+	if value.AssignableTo(field) {
+		return field, nil
+	}
+	if isNumeric(field) && isNumeric(value) {
+		return widen(field, value), nil
+	}
+	return nil, fmt.Errorf("expected %s, got %s", field, value)
+}
+
+// isWithin - reports whether column is path itself or nested under it
+func isWithin(column, path string) bool {
+	return column == path || strings.HasPrefix(column, path+".")
+}
+
+// entries - returns the leaf fields of the result with their values
+func (dr *DBResult) entries() []entry {
+	var entries []entry
+	for _, f := range dr.Fields() {
+		value := dr.Get(f.Column)
+		if dr.IsNull(f.Column) {
+			value = nil
+		}
+		entries = append(entries, entry{column: f.Column, typ: f.Type, value: value})
+	}
+	return entries
+}
+
+// rebuild - replaces the dynamic type and value of the result with the entries.
+// The driver values are kept; update adjusts them for the changed fields.
+func (dr *DBResult) rebuild(entries []entry, update func(raw map[string]any)) error {
+	result, err := buildResult(entries)
+	if err != nil {
+		return err
+	}
+
+	raw := result.raw
+	for column, val := range dr.raw {
+		if _, ok := raw[column]; ok {
+			raw[column] = val
+		}
+	}
+	update(raw)
+
+	dr.value = result.value
+	dr.typ = result.typ
+	dr.raw = raw
+	return nil
+}
+
+// buildResult - creates a DBResult holding the entries in order
+func buildResult(entries []entry) (*DBResult, error) {
+	cols := make([]column, len(entries))
+	values := make([]any, len(entries))
+
+	for i, e := range entries {
+		cols[i] = column{name: e.column, path: strings.Split(e.column, "."), typ: e.typ}
+		values[i] = e.value
+	}
+
+	if err := validateColumns(cols); err != nil {
+		return nil, err
+	}
+
+	structType, err := buildStructType(cols)
+	if err != nil {
+		return nil, err
+	}
+
+	return fillStruct(structType, cols, values, defaultConfig())
+}
+
+// buildSet - creates a DBResult per row of entries, giving every column a common type.
+// The type of a column is widened over its non-NULL values; columns
+// holding only NULLs keep the type of their entries.
+func buildSet(rows [][]entry) (ResultSet, error) {
+	types := make(map[string]reflect.Type)
+	for _, entries := range rows {
+		for _, e := range entries {
+			if e.value != nil {
+				types[e.column] = widen(types[e.column], reflect.TypeOf(e.value))
+			}
+		}
+	}
+
+	out := make(ResultSet, 0, len(rows))
+	for _, entries := range rows {
+		for i, e := range entries {
+			if typ, ok := types[e.column]; ok {
+				entries[i].typ = typ
+			}
+			if entries[i].typ == nil {
+				entries[i].typ = stringType
+			}
+		}
+
+		dr, err := buildResult(entries)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, dr)
+	}
+
+	return out, nil
+}
@@ -0,0 +1,119 @@
+package godyno
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestSetAndUnset(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	newResult := func(t *testing.T) *DBResult {
+		mock.ExpectQuery("SELECT").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "quantity", "price", "email", "address.city"}).
+				AddRow(1, []byte("3"), []byte("10"), "a@b.c", "Izmir"),
+		)
+		results, err := QueryToStruct(db, "SELECT * FROM orders")
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		return results[0]
+	}
+
+	t.Run("Overwrite a field", func(t *testing.T) {
+		result := newResult(t)
+		if err := result.Set("email", "***"); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		if got := result.GetString("email"); got != "***" {
+			t.Errorf("GetString(email) = %q, want ***", got)
+		}
+		// Other raw values survive the rebuild
+		if got := result.GetRaw("price"); string(got.([]byte)) != "10" {
+			t.Errorf("GetRaw(price) = %#v, want []byte(10)", got)
+		}
+	})
+
+	t.Run("Widen a numeric field", func(t *testing.T) {
+		result := newResult(t)
+		if err := result.Set("price", 10.5); err != nil {
+			t.Fatalf("Set() error = %v", err)
+		}
+		if got := result.Get("price"); got != 10.5 {
+			t.Errorf("Get(price) = %#v, want 10.5", got)
+		}
+	})
+
+	t.Run("Type mismatch", func(t *testing.T) {
+		result := newResult(t)
+		if err := result.Set("quantity", "many"); !errors.Is(err, ErrTypeMismatch) {
+			t.Errorf("Set() error = %v, want ErrTypeMismatch", err)
+		}
+		if err := result.Set("address", "x"); !errors.Is(err, ErrTypeMismatch) {
+			t.Errorf("Set(address) error = %v, want ErrTypeMismatch", err)
+		}
+		if got := result.GetInt("quantity"); got != 3 {
+			t.Errorf("GetInt(quantity) = %d, want unchanged 3", got)
+		}
+	})
+
+	t.Run("Computed and nested fields", func(t *testing.T) {
+		result := newResult(t)
+		total := float64(result.GetInt("quantity")) * result.GetFloat("price")
+		if err := result.Set("total", total); err != nil {
+			t.Fatalf("Set(total) error = %v", err)
+		}
+		if err := result.Set("address.country", "TR"); err != nil {
+			t.Fatalf("Set(address.country) error = %v", err)
+		}
+		if err := result.Set("email", nil); err != nil {
+			t.Fatalf("Set(email, nil) error = %v", err)
+		}
+
+		got, err := json.Marshal(result)
+		if err != nil {
+			t.Fatalf("json.Marshal() error = %v", err)
+		}
+		want := `{"id":1,"quantity":3,"price":10,"email":null,"address":{"city":"Izmir","country":"TR"},"total":30}`
+		if string(got) != want {
+			t.Errorf("json.Marshal() = %s, want %s", got, want)
+		}
+	})
+
+	t.Run("Unset", func(t *testing.T) {
+		result := newResult(t)
+		if err := result.Unset("email"); err != nil {
+			t.Fatalf("Unset(email) error = %v", err)
+		}
+		if err := result.Unset("address"); err != nil {
+			t.Fatalf("Unset(address) error = %v", err)
+		}
+		if result.Has("email") || result.Has("address.city") || result.Len() != 3 {
+			t.Errorf("Fields after Unset = %+v", result.Fields())
+		}
+		if err := result.Unset("missing"); !errors.Is(err, ErrFieldNotFound) {
+			t.Errorf("Unset(missing) error = %v, want ErrFieldNotFound", err)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestSetOnNew(t *testing.T) {
+	result := New()
+	if err := result.Set("id", 7); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := result.Set("id.value", 1); !errors.Is(err, ErrInvalidColumnName) {
+		t.Errorf("Set(id.value) error = %v, want ErrInvalidColumnName", err)
+	}
+	if result.GetInt("id") != 7 {
+		t.Errorf("GetInt(id) = %d, want 7", result.GetInt("id"))
+	}
+}
@@ -0,0 +1,76 @@
+package godyno
+
+import (
+	"bufio"
+	"io"
+)
+
+// NDJSONEncoder - writes results as newline-delimited JSON, one object per line,
+// using the same encoding as DBResult's MarshalJSON. Encode can be passed
+// directly to QueryEach to stream a query:
+//
+//	enc := godyno.NewNDJSONEncoder(w)
+//	err := godyno.QueryEach(db, query, enc.Encode)
+//	...
+//	err = enc.Flush()
+type NDJSONEncoder struct {
+	// FlushEvery flushes after every n rows; 0 only flushes on Flush
+	FlushEvery int
+
+	out     io.Writer
+	w       *bufio.Writer
+	pending int
+}
+
+// NewNDJSONEncoder - returns an encoder writing to w, flushing after every row
+func NewNDJSONEncoder(w io.Writer) *NDJSONEncoder {
+	return &NDJSONEncoder{FlushEvery: 1, out: w, w: bufio.NewWriter(w)}
+}
+
+// Encode - writes a row as a single JSON line
+func (e *NDJSONEncoder) Encode(dr *DBResult) error {
+	b, err := dr.MarshalJSON()
+	if err != nil {
+		return err
+	}
+
+	if _, err := e.w.Write(b); err != nil {
+		return err
+	}
+	if err := e.w.WriteByte('\n'); err != nil {
+		return err
+	}
+
+	e.pending++
+	if e.FlushEvery > 0 && e.pending >= e.FlushEvery {
+		return e.Flush()
+	}
+	return nil
+}
+
+// EncodeAll - writes every row and flushes
+func (e *NDJSONEncoder) EncodeAll(rs ResultSet) error {
+	for _, dr := range rs {
+		if err := e.Encode(dr); err != nil {
+			return err
+		}
+	}
+	return e.Flush()
+}
+
+// Flush - writes buffered lines and flushes the underlying writer if it
+// supports it (e.g. http.ResponseWriter)
+func (e *NDJSONEncoder) Flush() error {
+	e.pending = 0
+	if err := e.w.Flush(); err != nil {
+		return err
+	}
+
+	switch f := e.out.(type) {
+	case interface{ Flush() error }:
+		return f.Flush()
+	case interface{ Flush() }:
+		f.Flush()
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package godyno
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+// flushRecorder - records writes and flushes
+type flushRecorder struct {
+	strings.Builder
+	flushes int
+}
+
+func (f *flushRecorder) Flush() {
+	f.flushes++
+}
+
+func TestNDJSONEncoder(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "title", "address.city"}).
+			AddRow(1, "Desk", "Izmir").
+			AddRow(2, "Lamp", nil).
+			AddRow(3, "Mouse", "Ankara"),
+	)
+
+	out := &flushRecorder{}
+	enc := NewNDJSONEncoder(out)
+	enc.FlushEvery = 2
+
+	if err := QueryEach(db, "SELECT * FROM products", enc.Encode); err != nil {
+		t.Fatalf("QueryEach() error = %v", err)
+	}
+	if out.flushes != 1 {
+		t.Errorf("Flushes before Flush() = %d, want 1", out.flushes)
+	}
+	if err := enc.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+
+	want := `{"id":1,"title":"Desk","address":{"city":"Izmir"}}` + "\n" +
+		`{"id":2,"title":"Lamp","address":{"city":null}}` + "\n" +
+		`{"id":3,"title":"Mouse","address":{"city":"Ankara"}}` + "\n"
+	if out.String() != want {
+		t.Errorf("Output =\n%s\nwant\n%s", out.String(), want)
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestQueryEachStops(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT").WithArgs(5).WillReturnRows(
+		sqlmock.NewRows([]string{"id"}).AddRow(1).AddRow(2).AddRow(3),
+	)
+
+	stop := errors.New("stop")
+	seen := 0
+	err := QueryEach(db, "SELECT id FROM products WHERE category_id = $1", func(dr *DBResult) error {
+		seen++
+		if dr.GetInt("id") == 2 {
+			return stop
+		}
+		return nil
+	}, 5)
+
+	if !errors.Is(err, stop) || seen != 2 {
+		t.Errorf("QueryEach() = %v after %d rows, want stop after 2", err, seen)
+	}
+}
+
+func TestQueryEachPanic(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	defer func() {
+		if r := recover(); r != "boom" {
+			t.Errorf("QueryEach() recovered %v, want the panic of fn", r)
+		}
+	}()
+	err := QueryEach(db, "SELECT id FROM products", func(dr *DBResult) error {
+		panic("boom")
+	})
+	t.Errorf("QueryEach() = %v, want a panic", err)
+}
@@ -0,0 +1,146 @@
+package godyno
+
+import "strconv"
+
+// Option - configures a single QueryToStruct call or conversion
+type Option func(*config)
+
+// config - settings collected from the options of a call
+type config struct {
+	converters           *ConverterRegistry
+	strictStrings        bool
+	boolTrue             []string
+	boolFalse            []string
+	preserveLeadingZeros bool
+	sampleRows           int
+	flatKeys             bool
+	joinPrefix           string
+}
+
+// WithConverters - uses the registry's converters for type inference and value assignment
+func WithConverters(r *ConverterRegistry) Option {
+	return func(c *config) {
+		c.converters = r
+	}
+}
+
+// WithStrictStrings - keeps textual values as strings instead of sniffing numbers and booleans.
+// Registered converters still apply.
+func WithStrictStrings() Option {
+	return func(c *config) {
+		c.strictStrings = true
+	}
+}
+
+// WithBoolVocabulary - replaces the accepted boolean spellings with the given
+// true/false pairs, e.g. WithBoolVocabulary("yes", "no", "Y", "N").
+// Words are matched exactly and take precedence over numbers, so "1"/"0"
+// can be used as booleans. A trailing unpaired word is ignored.
+func WithBoolVocabulary(pairs ...string) Option {
+	return func(c *config) {
+		for i := 0; i+1 < len(pairs); i += 2 {
+			c.boolTrue = append(c.boolTrue, pairs[i])
+			c.boolFalse = append(c.boolFalse, pairs[i+1])
+		}
+	}
+}
+
+// WithPreserveLeadingZeros - keeps zero padded numbers such as "00123" as strings
+func WithPreserveLeadingZeros() Option {
+	return func(c *config) {
+		c.preserveLeadingZeros = true
+	}
+}
+
+// WithSampleRows - infers column types from the first n rows instead of only the first one.
+// Each column gets the narrowest type compatible with every sampled value;
+// n <= 0 samples the whole result. Sampled rows are buffered in memory.
+func WithSampleRows(n int) Option {
+	return func(c *config) {
+		c.sampleRows = n
+	}
+}
+
+// WithFlatKeys - makes ToMap use dotted column names as keys instead of nested maps
+func WithFlatKeys() Option {
+	return func(c *config) {
+		c.flatKeys = true
+	}
+}
+
+// WithJoinPrefix - nests the right side fields of Join under prefix
+func WithJoinPrefix(prefix string) Option {
+	return func(c *config) {
+		c.joinPrefix = prefix
+	}
+}
+
+// newConfig - separates the options from the query arguments
+func newConfig(args []any) (*config, []any) {
+	cfg := defaultConfig()
+	queryArgs := make([]any, 0, len(args))
+
+	for _, arg := range args {
+		if opt, ok := arg.(Option); ok {
+			opt(cfg)
+			continue
+		}
+		queryArgs = append(queryArgs, arg)
+	}
+
+	return cfg, queryArgs
+}
+
+// defaultConfig - returns the settings used when no option is given
+func defaultConfig() *config {
+	return &config{sampleRows: 1}
+}
+
+// hasBoolVocabulary - reports whether a custom boolean vocabulary is set
+func (c *config) hasBoolVocabulary() bool {
+	return len(c.boolTrue) > 0
+}
+
+// parseBool - parses a boolean using the configured vocabulary
+func (c *config) parseBool(s string) (bool, bool) {
+	if !c.hasBoolVocabulary() {
+		b, err := strconv.ParseBool(s)
+		return b, err == nil
+	}
+
+	for i := range c.boolTrue {
+		if s == c.boolTrue[i] {
+			return true, true
+		}
+		if s == c.boolFalse[i] {
+			return false, true
+		}
+	}
+	return false, false
+}
+
+// parseInt - parses an integer, honoring WithPreserveLeadingZeros
+func (c *config) parseInt(s string) (int, bool) {
+	if c.preserveLeadingZeros && hasLeadingZero(s) {
+		return 0, false
+	}
+	n, err := strconv.Atoi(s)
+	return n, err == nil
+}
+
+// parseFloat - parses a float, honoring WithPreserveLeadingZeros
+func (c *config) parseFloat(s string) (float64, bool) {
+	if c.preserveLeadingZeros && hasLeadingZero(s) {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	return f, err == nil
+}
+
+// hasLeadingZero - reports whether a number is zero padded, e.g. "007" or "-01.5"
+func hasLeadingZero(s string) bool {
+	if len(s) > 0 && (s[0] == '-' || s[0] == '+') {
+		s = s[1:]
+	}
+	return len(s) > 1 && s[0] == '0' && s[1] >= '0' && s[1] <= '9'
+}
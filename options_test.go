@@ -0,0 +1,88 @@
+package godyno
+
+import (
+	"database/sql/driver"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestInferenceOptions(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	columns := []string{"zip", "flag", "answer", "amount"}
+	row := []driver.Value{[]byte("00123"), []byte("1"), []byte("yes"), []byte("42")}
+
+	testCases := []struct {
+		name     string
+		opts     []any
+		expected map[string]any
+	}{
+		{
+			name: "Default inference",
+			expected: map[string]any{
+				"zip": 123, "flag": 1, "answer": "yes", "amount": 42,
+			},
+		},
+		{
+			name: "Strict strings",
+			opts: []any{WithStrictStrings()},
+			expected: map[string]any{
+				"zip": "00123", "flag": "1", "answer": "yes", "amount": "42",
+			},
+		},
+		{
+			name: "Bool vocabulary",
+			opts: []any{WithBoolVocabulary("yes", "no", "1", "0")},
+			expected: map[string]any{
+				"zip": 123, "flag": true, "answer": true, "amount": 42,
+			},
+		},
+		{
+			name: "Preserve leading zeros",
+			opts: []any{WithPreserveLeadingZeros()},
+			expected: map[string]any{
+				"zip": "00123", "flag": 1, "answer": "yes", "amount": 42,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows(columns).AddRow(row...))
+
+			results, err := QueryToStruct(db, "SELECT zip, flag, answer, amount FROM t", tc.opts...)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			for field, want := range tc.expected {
+				if got := results[0].Get(field); got != want {
+					t.Errorf("Get(%s) = %#v, want %#v", field, got, want)
+				}
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestHasLeadingZero(t *testing.T) {
+	testCases := map[string]bool{
+		"0":     false,
+		"0.5":   false,
+		"10":    false,
+		"007":   true,
+		"-01.5": true,
+		"":      false,
+	}
+
+	for input, want := range testCases {
+		if got := hasLeadingZero(input); got != want {
+			t.Errorf("hasLeadingZero(%q) = %v, want %v", input, got, want)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package godyno
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Pivot - turns the distinct values of columnKey into columns, one row per distinct rowKey.
+// Each cell aggregates valueField over the matching rows with aggFn (e.g.
+// godyno.Sum); cells without rows are NULL. Column values that are not
+// valid field names are sanitized, e.g. "2024-01" becomes "c2024_01".
+// Names that clash with rowKey or with an earlier column get a numeric
+// suffix, e.g. "a b" and "a-b" become "a_b" and "a_b_2". NULL gets its own
+// column named "null", apart from 0 and "".
+func (rs ResultSet) Pivot(rowKey, columnKey, valueField string, aggFn func(field string) Aggregation) (ResultSet, error) {
+	// Field names compare by their Go name, so "a" and "A" clash as well
+	used := map[string]bool{toTitle(strings.Split(rowKey, ".")[0]): true}
+
+	// Collect the pivoted column names in order of appearance
+	var names []string
+	nameOf := make(map[string]string)
+	for _, dr := range rs {
+		key, label := pivotColumn(dr, columnKey)
+		if _, ok := nameOf[key]; ok {
+			continue
+		}
+
+		base := pivotName(label)
+		name := base
+		for n := 2; used[toTitle(name)]; n++ {
+			name = fmt.Sprintf("%s_%d", base, n)
+		}
+		used[toTitle(name)] = true
+
+		nameOf[key] = name
+		names = append(names, name)
+	}
+
+	agg := aggFn(valueField)
+	var rows [][]entry
+
+	for _, group := range rs.GroupBy(rowKey) {
+		cells := make(map[string]ResultSet)
+		for _, dr := range group.Rows {
+			key, _ := pivotColumn(dr, columnKey)
+			name := nameOf[key]
+			cells[name] = append(cells[name], dr)
+		}
+
+		entries := group.Key.entries()
+		for _, name := range names {
+			e := entry{column: name, typ: fieldType(group.Rows[0], valueField)}
+			if cell, ok := cells[name]; ok {
+				e.value = agg.Func(cell, valueField)
+			}
+			entries = append(entries, e)
+		}
+		rows = append(rows, entries)
+	}
+
+	return buildSet(rows)
+}
+
+// pivotColumn - returns the key identifying the pivoted column of a row and the
+// label its name is made from. NULL must not share a column with 0 or "".
+func pivotColumn(dr *DBResult, columnKey string) (key, label string) {
+	val := fieldValue(dr, columnKey)
+	if val == nil {
+		return "<nil>", "null"
+	}
+	return fmt.Sprintf("%T:%v", val, val), fmt.Sprint(val)
+}
+
+// Unpivot - turns the given fields into rows holding "key" (the field name) and "value".
+// The other fields are repeated on every row. Values of different types
+// are widened to a common type, e.g. int and float64 to float64.
+func (rs ResultSet) Unpivot(fields ...string) (ResultSet, error) {
+	unpivoted := make(map[string]bool, len(fields))
+	for _, f := range fields {
+		unpivoted[f] = true
+	}
+
+	var rows [][]entry
+	for _, dr := range rs {
+		var kept []entry
+		for _, e := range dr.entries() {
+			if !unpivoted[e.column] {
+				kept = append(kept, e)
+			}
+		}
+
+		for _, f := range fields {
+			if !dr.Has(f) {
+				continue
+			}
+
+			entries := append([]entry{}, kept...)
+			entries = append(entries,
+				entry{column: "key", typ: stringType, value: f},
+				valueEntry(dr, f),
+			)
+			entries[len(entries)-1].column = "value"
+			rows = append(rows, entries)
+		}
+	}
+
+	return buildSet(rows)
+}
+
+// pivotName - turns a value into a usable field name
+func pivotName(value string) string {
+	var b strings.Builder
+	for _, r := range value {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('_')
+		}
+	}
+
+	name := b.String()
+	if !isIdentifier(toTitle(name)) {
+		name = "c" + name
+	}
+	return name
+}
@@ -0,0 +1,107 @@
+package godyno
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestPivot(t *testing.T) {
+	rs := newTestSet(t,
+		map[string]any{"region": "north", "month": "2024-01", "sales": int64(10)},
+		map[string]any{"region": "north", "month": "2024-01", "sales": int64(5)},
+		map[string]any{"region": "north", "month": "2024-02", "sales": int64(7)},
+		map[string]any{"region": "south", "month": "2024-02", "sales": int64(3)},
+	)
+
+	pivoted, err := rs.Pivot("region", "month", "sales", Sum)
+	if err != nil {
+		t.Fatalf("Pivot() error = %v", err)
+	}
+
+	got, _ := json.Marshal(pivoted)
+	want := `[{"region":"north","c2024_01":15,"c2024_02":7},{"region":"south","c2024_01":null,"c2024_02":3}]`
+	if string(got) != want {
+		t.Errorf("Pivot() = %s, want %s", got, want)
+	}
+
+	// Every row shares the same dynamic type
+	if pivoted[0].Type() != pivoted[1].Type() {
+		t.Errorf("Pivot() rows have different types: %v and %v", pivoted[0].Type(), pivoted[1].Type())
+	}
+}
+
+func TestPivotNameClashes(t *testing.T) {
+	rs := newTestSet(t,
+		map[string]any{"k": "x", "col": "a b", "v": int64(1)},
+		map[string]any{"k": "x", "col": "a-b", "v": int64(2)},
+		map[string]any{"k": "x", "col": "k", "v": int64(3)},
+		map[string]any{"k": "y", "col": "a_b_2", "v": int64(4)},
+	)
+
+	pivoted, err := rs.Pivot("k", "col", "v", Sum)
+	if err != nil {
+		t.Fatalf("Pivot() error = %v", err)
+	}
+
+	got, _ := json.Marshal(pivoted)
+	want := `[{"k":"x","a_b":1,"a_b_2":2,"k_2":3,"a_b_2_2":null},{"k":"y","a_b":null,"a_b_2":null,"k_2":null,"a_b_2_2":4}]`
+	if string(got) != want {
+		t.Errorf("Pivot() = %s, want %s", got, want)
+	}
+}
+
+func TestPivotNull(t *testing.T) {
+	rs := newTestSet(t,
+		map[string]any{"k": "x", "col": "", "v": int64(1)},
+		map[string]any{"k": "x", "col": nil, "v": int64(2)},
+		map[string]any{"k": "y", "col": nil, "v": int64(4)},
+	)
+
+	pivoted, err := rs.Pivot("k", "col", "v", Sum)
+	if err != nil {
+		t.Fatalf("Pivot() error = %v", err)
+	}
+
+	got, _ := json.Marshal(pivoted)
+	want := `[{"k":"x","c":1,"null":2},{"k":"y","c":null,"null":4}]`
+	if string(got) != want {
+		t.Errorf("Pivot() = %s, want %s", got, want)
+	}
+}
+
+func TestUnpivot(t *testing.T) {
+	rs := newTestSet(t,
+		map[string]any{"id": int64(1), "q1": int64(10), "q2": 12.5},
+		map[string]any{"id": int64(2), "q1": int64(4), "q2": nil},
+	)
+
+	unpivoted, err := rs.Unpivot("q1", "q2")
+	if err != nil {
+		t.Fatalf("Unpivot() error = %v", err)
+	}
+
+	got, _ := json.Marshal(unpivoted)
+	want := `[{"id":1,"key":"q1","value":10},{"id":1,"key":"q2","value":12.5},` +
+		`{"id":2,"key":"q1","value":4},{"id":2,"key":"q2","value":null}]`
+	if string(got) != want {
+		t.Errorf("Unpivot() = %s, want %s", got, want)
+	}
+	if _, ok := unpivoted[0].Get("value").(float64); !ok {
+		t.Errorf("Unpivot() value type = %T, want float64", unpivoted[0].Get("value"))
+	}
+}
+
+func TestPivotName(t *testing.T) {
+	testCases := map[string]string{
+		"jan":     "jan",
+		"2024-01": "c2024_01",
+		"a b":     "a_b",
+		"":        "c",
+	}
+
+	for input, want := range testCases {
+		if got := pivotName(input); got != want {
+			t.Errorf("pivotName(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
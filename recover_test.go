@@ -0,0 +1,137 @@
+package godyno
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestHostileColumnNames(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	testCases := []struct {
+		name    string
+		columns []string
+	}{
+		{"Empty name", []string{""}},
+		{"Space in name", []string{"first name"}},
+		{"Leading digit", []string{"1st"}},
+		{"Function call", []string{"count(*)"}},
+		{"Duplicate names", []string{"id", "id"}},
+		{"Names differing in case", []string{"id", "Id"}},
+		{"Value and parent", []string{"address", "address.city"}},
+		{"Empty path segment", []string{"address..city"}},
+		{"Trailing dot", []string{"address."}},
+		{"Leading underscore", []string{"_id"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			values := make([]driver.Value, len(tc.columns))
+			for i := range values {
+				values[i] = []byte("1")
+			}
+			mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows(tc.columns).AddRow(values...))
+
+			results, err := QueryToStruct(db, "SELECT hostile FROM t")
+			if !errors.Is(err, ErrInvalidColumnName) {
+				t.Errorf("Expected ErrInvalidColumnName, got %v (results: %v)", err, results)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestHostileValues(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	t.Run("Driver type changes between rows", func(t *testing.T) {
+		mock.ExpectQuery("SELECT").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "active"}).
+				AddRow(int64(1), true).
+				AddRow(int64(2), "maybe"),
+		)
+
+		_, err := QueryToStruct(db, "SELECT id, active FROM t")
+		var e *Error
+		if !errors.As(err, &e) || !errors.Is(err, ErrTypeMismatch) {
+			t.Fatalf("Expected ErrTypeMismatch, got %v", err)
+		}
+		if e.Column != "active" || e.Row != 1 {
+			t.Errorf("Error column = %q, row = %d, want active and 1", e.Column, e.Row)
+		}
+	})
+
+	t.Run("Converter returns the wrong type", func(t *testing.T) {
+		registry := NewConverterRegistry()
+		registry.RegisterColumn("status", Converter{
+			Type:    stringType,
+			Convert: func(src any) (any, error) { return 42, nil },
+		})
+		mock.ExpectQuery("SELECT").WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow("on"))
+
+		_, err := QueryToStruct(db, "SELECT status FROM t", WithConverters(registry))
+		if !errors.Is(err, ErrTypeMismatch) {
+			t.Errorf("Expected ErrTypeMismatch, got %v", err)
+		}
+	})
+
+	t.Run("Converter panics", func(t *testing.T) {
+		registry := NewConverterRegistry()
+		registry.RegisterColumn("status", NewConverter(func(src any) (string, error) {
+			panic("boom")
+		}))
+		mock.ExpectQuery("SELECT").WillReturnRows(
+			sqlmock.NewRows([]string{"id", "status"}).AddRow(1, "on"),
+		)
+
+		_, err := QueryToStruct(db, "SELECT id, status FROM t", WithConverters(registry))
+		var e *Error
+		if !errors.As(err, &e) || !errors.Is(err, ErrReflection) {
+			t.Fatalf("Expected ErrReflection, got %v", err)
+		}
+		if e.Column != "status" || e.Row != 0 {
+			t.Errorf("Error column = %q, row = %d, want status and 0", e.Column, e.Row)
+		}
+	})
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestMethodsDoNotPanic(t *testing.T) {
+	hostile := []*DBResult{
+		nil,
+		New(),
+		{value: 42},
+		{value: struct{ hidden int }{1}},
+	}
+
+	for _, dr := range hostile {
+		if _, err := dr.Lookup("hidden"); err == nil {
+			t.Errorf("Lookup on %#v should fail", dr)
+		}
+		_ = dr.Get("a.b")
+		_ = dr.GetString("a")
+		_ = dr.GetInt("a")
+		_ = dr.GetFloat("a")
+		_ = dr.GetBool("a")
+		_ = dr.GetBytes("a")
+		_ = dr.GetRaw("a")
+		_ = dr.IsNull("a")
+		_ = dr.Metadata()
+	}
+
+	if _, err := json.Marshal(&DBResult{value: 42}); !errors.Is(err, ErrReflection) {
+		t.Errorf("json.Marshal of a non-struct value = %v, want ErrReflection", err)
+	}
+}
@@ -0,0 +1,262 @@
+package godyno
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ResultSet - the rows of a query result, with helpers working on the dynamic fields via Get
+type ResultSet []*DBResult
+
+// Filter - returns the rows for which fn returns true
+func (rs ResultSet) Filter(fn func(*DBResult) bool) ResultSet {
+	out := ResultSet{}
+	for _, dr := range rs {
+		if fn(dr) {
+			out = append(out, dr)
+		}
+	}
+	return out
+}
+
+// Map - returns the results of fn for every row; nil results are dropped
+func (rs ResultSet) Map(fn func(*DBResult) *DBResult) ResultSet {
+	out := make(ResultSet, 0, len(rs))
+	for _, dr := range rs {
+		if mapped := fn(dr); mapped != nil {
+			out = append(out, mapped)
+		}
+	}
+	return out
+}
+
+// SortBy - returns a copy sorted by the given fields, e.g. SortBy("category", "-price").
+// A leading "-" or a trailing " desc" sorts a field in descending order.
+// NULL and missing values sort first; equal rows keep their order.
+func (rs ResultSet) SortBy(fields ...string) ResultSet {
+	type sortKey struct {
+		field string
+		desc  bool
+	}
+
+	keys := make([]sortKey, 0, len(fields))
+	for _, f := range fields {
+		f = strings.TrimSpace(f)
+		key := sortKey{field: f}
+
+		if lower := strings.ToLower(f); strings.HasSuffix(lower, " desc") {
+			key = sortKey{field: strings.TrimSpace(f[:len(f)-5]), desc: true}
+		} else if strings.HasSuffix(lower, " asc") {
+			key.field = strings.TrimSpace(f[:len(f)-4])
+		} else if strings.HasPrefix(f, "-") {
+			key = sortKey{field: f[1:], desc: true}
+		}
+		keys = append(keys, key)
+	}
+
+	out := append(ResultSet{}, rs...)
+	sort.SliceStable(out, func(i, j int) bool {
+		for _, key := range keys {
+			c := compareForSort(fieldValue(out[i], key.field), fieldValue(out[j], key.field))
+			if c == 0 {
+				continue
+			}
+			if key.desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+
+	return out
+}
+
+// First - returns the first row, or nil for an empty set
+func (rs ResultSet) First() *DBResult {
+	if len(rs) == 0 {
+		return nil
+	}
+	return rs[0]
+}
+
+// Last - returns the last row, or nil for an empty set
+func (rs ResultSet) Last() *DBResult {
+	if len(rs) == 0 {
+		return nil
+	}
+	return rs[len(rs)-1]
+}
+
+// Pluck - returns the value of a field for every row
+func (rs ResultSet) Pluck(fieldName string) []any {
+	values := make([]any, len(rs))
+	for i, dr := range rs {
+		values[i] = dr.Get(fieldName)
+	}
+	return values
+}
+
+// Where - returns the rows whose field compares to value with op.
+// Supported operators: =, ==, !=, <>, <, <=, >, >=, in (value is a slice)
+// and contains (substring). Numbers compare across types, so an int64
+// column matches an int value. Like in SQL, a NULL field matches no
+// comparison with a value; use Where(field, "=", nil) to select NULLs.
+// Values that can't be compared, and unknown operators, match nothing.
+func (rs ResultSet) Where(fieldName, op string, value any) ResultSet {
+	return rs.Filter(func(dr *DBResult) bool {
+		return matchValue(fieldValue(dr, fieldName), strings.ToLower(strings.TrimSpace(op)), value)
+	})
+}
+
+// fieldValue - returns the value of a field, nil when it is NULL or missing.
+// Get returns the zero value for NULL, which must not compare equal to 0 or "".
+func fieldValue(dr *DBResult, fieldName string) any {
+	if dr.IsNull(fieldName) {
+		return nil
+	}
+	return dr.Get(fieldName)
+}
+
+// matchValue - applies a Where operator
+func matchValue(field any, op string, value any) bool {
+	switch op {
+	case "in":
+		list := reflect.ValueOf(value)
+		if list.Kind() != reflect.Slice && list.Kind() != reflect.Array {
+			return false
+		}
+		for i := 0; i < list.Len(); i++ {
+			if c, ok := compareValues(field, list.Index(i).Interface()); ok && c == 0 {
+				return true
+			}
+		}
+		return false
+	case "contains":
+		s, ok := field.(string)
+		sub, subOK := value.(string)
+		return ok && subOK && strings.Contains(s, sub)
+	}
+
+	if field == nil && value != nil {
+		return false
+	}
+
+	c, ok := compareValues(field, value)
+	if !ok {
+		return false
+	}
+
+	switch op {
+	case "=", "==":
+		return c == 0
+	case "!=", "<>":
+		return c != 0
+	case "<":
+		return c < 0
+	case "<=":
+		return c <= 0
+	case ">":
+		return c > 0
+	case ">=":
+		return c >= 0
+	}
+	return false
+}
+
+// compareValues - compares two field values; ok is false when their types can't be compared.
+// Numbers compare across numeric types and nil is smaller than any value.
+func compareValues(a, b any) (int, bool) {
+	switch {
+	case a == nil && b == nil:
+		return 0, true
+	case a == nil:
+		return -1, true
+	case b == nil:
+		return 1, true
+	}
+
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	if isNumeric(va.Type()) && isNumeric(vb.Type()) {
+		if !isFloat(va.Type()) && !isFloat(vb.Type()) {
+			return compareOrdered(toInt64(va), toInt64(vb)), true
+		}
+		return compareOrdered(toFloat64(va), toFloat64(vb)), true
+	}
+
+	switch x := a.(type) {
+	case string:
+		if y, ok := b.(string); ok {
+			return strings.Compare(x, y), true
+		}
+	case bool:
+		if y, ok := b.(bool); ok {
+			return compareOrdered(boolRank(x), boolRank(y)), true
+		}
+	case time.Time:
+		if y, ok := b.(time.Time); ok {
+			return x.Compare(y), true
+		}
+	case []byte:
+		if y, ok := b.([]byte); ok {
+			return bytes.Compare(x, y), true
+		}
+	}
+
+	// Named types such as custom string enums
+	if va.Kind() == vb.Kind() && va.Kind() == reflect.String {
+		return strings.Compare(va.String(), vb.String()), true
+	}
+
+	return 0, false
+}
+
+// compareForSort - compares any two values, falling back to their text form
+func compareForSort(a, b any) int {
+	if c, ok := compareValues(a, b); ok {
+		return c
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// compareOrdered - three-way comparison of ordered values
+func compareOrdered[T int64 | float64 | int](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+// toInt64 - returns an integer kind value as int64
+func toInt64(v reflect.Value) int64 {
+	if v.CanUint() {
+		return int64(v.Uint())
+	}
+	return v.Int()
+}
+
+// toFloat64 - returns a numeric kind value as float64
+func toFloat64(v reflect.Value) float64 {
+	switch {
+	case v.CanFloat():
+		return v.Float()
+	case v.CanUint():
+		return float64(v.Uint())
+	}
+	return float64(v.Int())
+}
+
+// boolRank - orders false before true
+func boolRank(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
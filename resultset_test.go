@@ -0,0 +1,132 @@
+package godyno
+
+import (
+	"reflect"
+	"testing"
+)
+
+// newTestSet - builds a ResultSet from maps
+func newTestSet(t *testing.T, rows ...map[string]any) ResultSet {
+	t.Helper()
+
+	rs := ResultSet{}
+	for _, row := range rows {
+		dr, err := FromMap(row)
+		if err != nil {
+			t.Fatalf("FromMap() error = %v", err)
+		}
+		rs = append(rs, dr)
+	}
+	return rs
+}
+
+// productSet - sample rows used by the ResultSet tests
+func productSet(t *testing.T) ResultSet {
+	return newTestSet(t,
+		map[string]any{"id": int64(1), "title": "Keyboard", "category": "tech", "price": 49.9, "active": true},
+		map[string]any{"id": int64(2), "title": "Desk", "category": "home", "price": 199.0, "active": false},
+		map[string]any{"id": int64(3), "title": "Mouse", "category": "tech", "price": 19.9, "active": true},
+		map[string]any{"id": int64(4), "title": "Lamp", "category": "home", "price": 19.9, "active": true},
+	)
+}
+
+func TestResultSetHelpers(t *testing.T) {
+	rs := productSet(t)
+
+	t.Run("Filter and Map", func(t *testing.T) {
+		active := rs.Filter(func(dr *DBResult) bool { return dr.GetBool("active") })
+		if len(active) != 3 {
+			t.Errorf("Filter() returned %d rows, want 3", len(active))
+		}
+
+		titles := rs.Map(func(dr *DBResult) *DBResult {
+			if dr.GetFloat("price") > 100 {
+				return nil
+			}
+			return dr
+		}).Pluck("title")
+		if !reflect.DeepEqual(titles, []any{"Keyboard", "Mouse", "Lamp"}) {
+			t.Errorf("Map().Pluck(title) = %v", titles)
+		}
+	})
+
+	t.Run("SortBy", func(t *testing.T) {
+		got := rs.SortBy("price", "-id").Pluck("id")
+		if want := []any{int64(4), int64(3), int64(1), int64(2)}; !reflect.DeepEqual(got, want) {
+			t.Errorf("SortBy(price, -id) = %v, want %v", got, want)
+		}
+
+		got = rs.SortBy("category desc", "title").Pluck("title")
+		if want := []any{"Keyboard", "Mouse", "Desk", "Lamp"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("SortBy(category desc, title) = %v, want %v", got, want)
+		}
+
+		// The original order is untouched
+		if rs.First().GetInt("id") != 1 || rs.Last().GetInt("id") != 4 {
+			t.Error("SortBy() modified the receiver")
+		}
+	})
+
+	t.Run("Where", func(t *testing.T) {
+		testCases := []struct {
+			field, op string
+			value     any
+			want      []any
+		}{
+			{"category", "=", "tech", []any{int64(1), int64(3)}},
+			{"price", ">", 20, []any{int64(1), int64(2)}},
+			{"price", "<=", 19.9, []any{int64(3), int64(4)}},
+			{"id", "!=", 2, []any{int64(1), int64(3), int64(4)}},
+			{"id", "in", []int{2, 4}, []any{int64(2), int64(4)}},
+			{"title", "contains", "e", []any{int64(1), int64(2), int64(3)}},
+			{"title", ">", 5, []any{}},
+			{"title", "~", "x", []any{}},
+		}
+
+		for _, tc := range testCases {
+			got := rs.Where(tc.field, tc.op, tc.value).Pluck("id")
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Where(%s %s %v) = %v, want %v", tc.field, tc.op, tc.value, got, tc.want)
+			}
+		}
+	})
+
+	t.Run("NULL values", func(t *testing.T) {
+		rs := newTestSet(t,
+			map[string]any{"id": int64(1), "qty": int64(5)},
+			map[string]any{"id": int64(2), "qty": nil},
+			map[string]any{"id": int64(3), "qty": int64(0)},
+		)
+
+		testCases := []struct {
+			op    string
+			value any
+			want  []any
+		}{
+			{"=", 0, []any{int64(3)}},
+			{"<", 5, []any{int64(3)}},
+			{"!=", 5, []any{int64(3)}},
+			{"=", nil, []any{int64(2)}},
+			{"!=", nil, []any{int64(1), int64(3)}},
+		}
+		for _, tc := range testCases {
+			if got := rs.Where("qty", tc.op, tc.value).Pluck("id"); !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("Where(qty %s %v) = %v, want %v", tc.op, tc.value, got, tc.want)
+			}
+		}
+
+		if got := rs.SortBy("qty").Pluck("id"); !reflect.DeepEqual(got, []any{int64(2), int64(3), int64(1)}) {
+			t.Errorf("SortBy(qty) = %v, want NULL first", got)
+		}
+		if got := rs.SortBy("-qty").Pluck("id"); !reflect.DeepEqual(got, []any{int64(1), int64(3), int64(2)}) {
+			t.Errorf("SortBy(-qty) = %v, want NULL last", got)
+		}
+	})
+
+	t.Run("Empty set", func(t *testing.T) {
+		var empty ResultSet
+		if empty.First() != nil || empty.Last() != nil || len(empty.SortBy("id")) != 0 {
+			t.Error("Helpers on an empty set should return empty values")
+		}
+	})
+}
@@ -0,0 +1,118 @@
+package godyno
+
+import (
+	"database/sql/driver"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// FromStruct - builds a DBResult from a Go struct (or pointer to one), so values
+// from other sources can use the same getters and exporters as query results.
+// Field names come from the db tag, then the json tag, then the Go name; "-"
+// skips a field. Embedded and nested structs become dotted paths such as
+// "address.city". A field that refers back to a struct being expanded (e.g.
+// Parent *Node inside Node) is skipped. Returns nil if v is not a struct or a
+// name is not a valid field name (e.g. json:"first-name"); use FromStructE to
+// tell the two apart.
+func FromStruct(v any) *DBResult {
+	result, _ := FromStructE(v)
+	return result
+}
+
+// FromStructE - like FromStruct, but returns an ErrTypeMismatch error for a
+// non-struct value and an ErrInvalidColumnName error for an unusable name
+func FromStructE(v any) (*DBResult, error) {
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Pointer {
+		if val.IsNil() {
+			return nil, newError(ErrTypeMismatch, "", fmt.Errorf("expected a struct, got nil %T", v))
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil, newError(ErrTypeMismatch, "", fmt.Errorf("expected a struct, got %T", v))
+	}
+
+	var entries []entry
+	structEntries(val, "", map[reflect.Type]bool{}, &entries)
+
+	return buildResult(entries)
+}
+
+// structEntries - appends the leaf fields of a struct value as entries in field order.
+// path holds the struct types being expanded, so recursive types end.
+func structEntries(val reflect.Value, prefix string, path map[reflect.Type]bool, entries *[]entry) {
+	path[val.Type()] = true
+	defer delete(path, val.Type())
+
+	for i := 0; i < val.NumField(); i++ {
+		sf := val.Type().Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		name := structFieldName(sf)
+		if name == "-" {
+			continue
+		}
+
+		field := val.Field(i)
+		typ := sf.Type
+
+		// Follow pointers to nested structs; a nil pointer gives zero values
+		if typ.Kind() == reflect.Pointer && isPlainStruct(typ.Elem()) {
+			typ = typ.Elem()
+			if field.IsNil() {
+				field = reflect.Zero(typ)
+			} else {
+				field = field.Elem()
+			}
+		}
+
+		if isPlainStruct(typ) {
+			if path[typ] {
+				continue
+			}
+			structEntries(field, prefix+name+".", path, entries)
+			continue
+		}
+
+		*entries = append(*entries, entry{column: prefix + name, typ: typ, value: field.Interface()})
+	}
+}
+
+// structFieldName - returns the column name of a struct field from its db or json tag
+func structFieldName(sf reflect.StructField) string {
+	for _, key := range []string{"db", "json"} {
+		if name, _, _ := strings.Cut(sf.Tag.Get(key), ","); name != "" {
+			return name
+		}
+	}
+	return sf.Name
+}
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	valuerType        = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// isPlainStruct - reports whether a struct type should be split into nested fields.
+// Types with their own encoding (time.Time, sql.NullString, ...) are kept whole.
+func isPlainStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t == timeType {
+		return false
+	}
+
+	for _, iface := range []reflect.Type{jsonMarshalerType, textMarshalerType, valuerType} {
+		if t.Implements(iface) || reflect.PointerTo(t).Implements(iface) {
+			return false
+		}
+	}
+	return true
+}
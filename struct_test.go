@@ -0,0 +1,122 @@
+package godyno
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+)
+
+type Audit struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type testAddress struct {
+	City string `json:"city"`
+	Zip  string `db:"zip_code" json:"zip"`
+}
+
+type testProduct struct {
+	Audit
+	ID       int            `db:"id"`
+	Title    string         `json:"title,omitempty"`
+	Price    float64        // no tag
+	Secret   string         `json:"-"`
+	Address  testAddress    `json:"address"`
+	Billing  *testAddress   `json:"billing"`
+	Note     sql.NullString `json:"note"`
+	internal string
+}
+
+func TestFromStruct(t *testing.T) {
+	created := time.Date(2024, 1, 15, 10, 0, 0, 0, time.UTC)
+	product := testProduct{
+		Audit:    Audit{CreatedAt: created},
+		ID:       7,
+		Title:    "Product 7",
+		Price:    9.99,
+		Secret:   "hidden",
+		Address:  testAddress{City: "Izmir", Zip: "35000"},
+		internal: "x",
+	}
+
+	result := FromStruct(&product)
+	if result == nil {
+		t.Fatal("FromStruct() returned nil")
+	}
+
+	if result.GetInt("id") != 7 || result.GetString("title") != "Product 7" || result.GetFloat("Price") != 9.99 {
+		t.Errorf("Unexpected values: %v", result.ToMap(WithFlatKeys()))
+	}
+	if result.GetString("address.city") != "Izmir" || result.GetString("address.zip_code") != "35000" {
+		t.Errorf("Unexpected nested values: %v", result.ToMap(WithFlatKeys()))
+	}
+	if got := result.Get("audit.created_at"); got != created {
+		t.Errorf("Get(audit.created_at) = %v, want %v", got, created)
+	}
+	if result.Has("Secret") || result.Has("internal") {
+		t.Error("Skipped fields should not be present")
+	}
+	if !result.Has("billing.city") {
+		t.Error("A nil nested pointer should give zero valued fields")
+	}
+	if _, ok := result.Get("note").(sql.NullString); !ok {
+		t.Errorf("Get(note) = %T, want sql.NullString", result.Get("note"))
+	}
+
+	if _, err := json.Marshal(result); err != nil {
+		t.Errorf("json.Marshal() error = %v", err)
+	}
+}
+
+func TestFromStructInvalid(t *testing.T) {
+	var nilProduct *testProduct
+	for _, v := range []any{nil, 42, "text", nilProduct} {
+		if result := FromStruct(v); result != nil {
+			t.Errorf("FromStruct(%#v) = %v, want nil", v, result)
+		}
+	}
+}
+
+func TestFromStructE(t *testing.T) {
+	if _, err := FromStructE(42); !errors.Is(err, ErrTypeMismatch) {
+		t.Errorf("FromStructE(42) error = %v, want ErrTypeMismatch", err)
+	}
+
+	type badTag struct {
+		FirstName string `json:"first-name"`
+	}
+	if _, err := FromStructE(badTag{FirstName: "Ada"}); !errors.Is(err, ErrInvalidColumnName) {
+		t.Errorf("FromStructE(badTag) error = %v, want ErrInvalidColumnName", err)
+	}
+	if result := FromStruct(badTag{}); result != nil {
+		t.Errorf("FromStruct(badTag) = %v, want nil", result)
+	}
+
+	result, err := FromStructE(&testAddress{City: "Izmir"})
+	if err != nil || result.GetString("city") != "Izmir" {
+		t.Errorf("FromStructE() = %v, %v", result, err)
+	}
+}
+
+type testNode struct {
+	Name   string    `json:"name"`
+	Parent *testNode `json:"parent"`
+	Owner  struct {
+		Name string    `json:"name"`
+		Home *testNode `json:"home"`
+	} `json:"owner"`
+}
+
+func TestFromStructRecursive(t *testing.T) {
+	result, err := FromStructE(testNode{Name: "root"})
+	if err != nil {
+		t.Fatalf("FromStructE() error = %v", err)
+	}
+
+	got, _ := json.Marshal(result)
+	if want := `{"name":"root","owner":{"name":""}}`; string(got) != want {
+		t.Errorf("FromStructE() = %s, want %s", got, want)
+	}
+}
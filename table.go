@@ -0,0 +1,242 @@
+package godyno
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// TableStyle - the border style of a rendered table
+type TableStyle int
+
+const (
+	// ASCIITable draws borders with +, - and |
+	ASCIITable TableStyle = iota
+	// UnicodeTable draws borders with box drawing characters
+	UnicodeTable
+	// MarkdownTable writes a GitHub flavored Markdown table
+	MarkdownTable
+)
+
+// tableBorder - the characters of a border line: left, fill, separator, right
+type tableBorder [4]string
+
+var tableBorders = map[TableStyle]struct {
+	top, middle, bottom tableBorder
+	vertical, ellipsis  string
+}{
+	ASCIITable: {
+		top:      tableBorder{"+", "-", "+", "+"},
+		middle:   tableBorder{"+", "-", "+", "+"},
+		bottom:   tableBorder{"+", "-", "+", "+"},
+		vertical: "|",
+		ellipsis: "...",
+	},
+	UnicodeTable: {
+		top:      tableBorder{"┌", "─", "┬", "┐"},
+		middle:   tableBorder{"├", "─", "┼", "┤"},
+		bottom:   tableBorder{"└", "─", "┴", "┘"},
+		vertical: "│",
+		ellipsis: "…",
+	},
+	MarkdownTable: {
+		vertical: "|",
+		ellipsis: "…",
+	},
+}
+
+// TableWriter - renders results as an aligned text table for terminals and
+// Markdown documents. Columns are taken from the first row, nested fields
+// appear as dotted headers and numeric columns are right aligned.
+type TableWriter struct {
+	// Style is the border style, ASCIITable by default
+	Style TableStyle
+	// MaxColumnWidth truncates longer cells, 0 means no limit
+	MaxColumnWidth int
+	// MaxWidth shrinks the widest columns until a line fits, 0 means no limit
+	MaxWidth int
+	// Null is written for NULL values, "NULL" by default
+	Null string
+	// TimeFormat is the layout for time.Time values, time.RFC3339 by default
+	TimeFormat string
+
+	out io.Writer
+}
+
+// NewTableWriter - returns a TableWriter writing to w
+func NewTableWriter(w io.Writer) *TableWriter {
+	return &TableWriter{Null: "NULL", out: w}
+}
+
+// Table - renders the result set as an ASCII table
+func (rs ResultSet) Table() string {
+	var b strings.Builder
+	_ = NewTableWriter(&b).WriteAll(rs)
+	return b.String()
+}
+
+// WriteAll - renders every row as one table; nothing is written for an empty set
+func (tw *TableWriter) WriteAll(rs ResultSet) error {
+	if len(rs) == 0 {
+		return nil
+	}
+
+	fields := rs[0].Fields()
+	right := make([]bool, len(fields))
+	header := make([]string, len(fields))
+	for i, field := range fields {
+		header[i] = tw.clean(field.Column)
+		right[i] = isNumeric(field.Type)
+	}
+
+	rows := make([][]string, len(rs))
+	for r, dr := range rs {
+		rows[r] = make([]string, len(fields))
+		for i, field := range fields {
+			text := tw.Null
+			if !dr.IsNull(field.Column) && dr.Has(field.Column) {
+				text = formatValue(dr.Get(field.Column), tw.TimeFormat)
+			}
+			rows[r][i] = tw.clean(text)
+		}
+	}
+
+	widths := tw.widths(header, rows)
+	border := tableBorders[tw.Style]
+
+	w := bufio.NewWriter(tw.out)
+	if tw.Style == MarkdownTable {
+		tw.line(w, header, widths, right)
+		w.WriteString("|")
+		for i, width := range widths {
+			dashes := strings.Repeat("-", width)
+			if right[i] {
+				dashes = dashes[1:] + ":"
+			}
+			w.WriteString(" " + dashes + " |")
+		}
+		w.WriteString("\n")
+	} else {
+		tw.rule(w, border.top, widths)
+		tw.line(w, header, widths, right)
+		tw.rule(w, border.middle, widths)
+	}
+
+	for _, row := range rows {
+		tw.line(w, row, widths, right)
+	}
+	if tw.Style != MarkdownTable {
+		tw.rule(w, border.bottom, widths)
+	}
+
+	return w.Flush()
+}
+
+// widths - returns the column widths after applying MaxColumnWidth and MaxWidth
+func (tw *TableWriter) widths(header []string, rows [][]string) []int {
+	widths := make([]int, len(header))
+	for i, h := range header {
+		widths[i] = utf8.RuneCountInString(h)
+	}
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], utf8.RuneCountInString(cell))
+		}
+	}
+
+	minWidth := 1
+	if tw.Style == MarkdownTable {
+		minWidth = 3 // room for the "---" alignment row
+	}
+	for i := range widths {
+		if tw.MaxColumnWidth > 0 {
+			widths[i] = min(widths[i], tw.MaxColumnWidth)
+		}
+		widths[i] = max(widths[i], minWidth)
+	}
+
+	if tw.MaxWidth <= 0 {
+		return widths
+	}
+
+	// a line is "| a | b |": three characters per column plus the closing border
+	total := 1
+	for _, width := range widths {
+		total += width + 3
+	}
+	for total > tw.MaxWidth {
+		widest := 0
+		for i, width := range widths {
+			if width > widths[widest] {
+				widest = i
+			}
+		}
+		if widths[widest] <= max(minWidth, 3) {
+			break
+		}
+		widths[widest]--
+		total--
+	}
+
+	return widths
+}
+
+// line - writes a row of cells padded to the column widths
+func (tw *TableWriter) line(w *bufio.Writer, cells []string, widths []int, right []bool) {
+	vertical := tableBorders[tw.Style].vertical
+
+	w.WriteString(vertical)
+	for i, cell := range cells {
+		cell = tw.truncate(cell, widths[i])
+		pad := strings.Repeat(" ", widths[i]-utf8.RuneCountInString(cell))
+		if right[i] {
+			cell = pad + cell
+		} else {
+			cell += pad
+		}
+		w.WriteString(" " + cell + " " + vertical)
+	}
+	w.WriteString("\n")
+}
+
+// rule - writes a horizontal border line
+func (tw *TableWriter) rule(w *bufio.Writer, b tableBorder, widths []int) {
+	w.WriteString(b[0])
+	for i, width := range widths {
+		if i > 0 {
+			w.WriteString(b[2])
+		}
+		w.WriteString(strings.Repeat(b[1], width+2))
+	}
+	w.WriteString(b[3] + "\n")
+}
+
+// truncate - shortens text to width characters, ending with an ellipsis
+func (tw *TableWriter) truncate(text string, width int) string {
+	if utf8.RuneCountInString(text) <= width {
+		return text
+	}
+
+	ellipsis := tableBorders[tw.Style].ellipsis
+	runes := []rune(text)
+	keep := width - utf8.RuneCountInString(ellipsis)
+	if keep <= 0 {
+		return string(runes[:width])
+	}
+	kept := string(runes[:keep])
+	if tw.Style == MarkdownTable {
+		// do not leave half of an escaped "\|"
+		kept = strings.TrimSuffix(kept, `\`)
+	}
+	return kept + ellipsis
+}
+
+// clean - keeps cells on one line and escapes the Markdown column separator
+func (tw *TableWriter) clean(text string) string {
+	text = strings.NewReplacer("\r\n", " ", "\n", " ", "\r", " ", "\t", " ").Replace(text)
+	if tw.Style == MarkdownTable {
+		text = strings.ReplaceAll(text, "|", `\|`)
+	}
+	return text
+}
@@ -0,0 +1,92 @@
+package godyno
+
+import (
+	"strings"
+	"testing"
+)
+
+// tableSet - rows with a nested field and a NULL used by the table tests
+func tableSet(t *testing.T) ResultSet {
+	return newTestSet(t,
+		map[string]any{"id": int64(1), "title": "Keyboard", "price": 49.9, "address.city": "Izmir"},
+		map[string]any{"id": int64(12), "title": "Desk|oak", "price": 199.0, "address.city": nil},
+	)
+}
+
+func TestTableWriter(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(tw *TableWriter)
+		want  string
+	}{
+		{
+			name:  "ascii",
+			setup: func(tw *TableWriter) {},
+			want: "" +
+				"+--------------+----+-------+----------+\n" +
+				"| address.city | id | price | title    |\n" +
+				"+--------------+----+-------+----------+\n" +
+				"| Izmir        |  1 |  49.9 | Keyboard |\n" +
+				"| NULL         | 12 |   199 | Desk|oak |\n" +
+				"+--------------+----+-------+----------+\n",
+		},
+		{
+			name: "unicode with max width",
+			setup: func(tw *TableWriter) {
+				tw.Style = UnicodeTable
+				tw.MaxWidth = 30
+			},
+			want: "" +
+				"┌───────┬────┬───────┬───────┐\n" +
+				"│ addr… │ id │ price │ title │\n" +
+				"├───────┼────┼───────┼───────┤\n" +
+				"│ Izmir │  1 │  49.9 │ Keyb… │\n" +
+				"│ NULL  │ 12 │   199 │ Desk… │\n" +
+				"└───────┴────┴───────┴───────┘\n",
+		},
+		{
+			name: "markdown",
+			setup: func(tw *TableWriter) {
+				tw.Style = MarkdownTable
+				tw.Null = ""
+			},
+			want: "" +
+				"| address.city |  id | price | title     |\n" +
+				"| ------------ | --: | ----: | --------- |\n" +
+				"| Izmir        |   1 |  49.9 | Keyboard  |\n" +
+				"|              |  12 |   199 | Desk\\|oak |\n",
+		},
+		{
+			name: "max column width",
+			setup: func(tw *TableWriter) {
+				tw.MaxColumnWidth = 5
+			},
+			want: "" +
+				"+-------+----+-------+-------+\n" +
+				"| ad... | id | price | title |\n" +
+				"+-------+----+-------+-------+\n" +
+				"| Izmir |  1 |  49.9 | Ke... |\n" +
+				"| NULL  | 12 |   199 | De... |\n" +
+				"+-------+----+-------+-------+\n",
+		},
+	}
+
+	rs := tableSet(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			tw := NewTableWriter(&b)
+			tt.setup(tw)
+			if err := tw.WriteAll(rs); err != nil {
+				t.Fatalf("WriteAll() error = %v", err)
+			}
+			if b.String() != tt.want {
+				t.Errorf("WriteAll() =\n%s\nwant\n%s", b.String(), tt.want)
+			}
+		})
+	}
+
+	if got := (ResultSet{}).Table(); got != "" {
+		t.Errorf("Table() of an empty set = %q, want empty", got)
+	}
+}
@@ -0,0 +1,270 @@
+package godyno
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// XLSX cell styles, indexes into cellXfs of styles.xml
+const (
+	xlsxStyleDefault = iota
+	xlsxStyleHeader
+	xlsxStyleHeaderRight
+	xlsxStyleDate
+)
+
+// XLSXWriter - writes results as an Excel workbook with a single sheet.
+// Numbers, booleans and times are written as typed cells, everything else as
+// text. The header is taken from the columns of the first row and styled bold;
+// column widths are estimated from the field types. Rows are streamed into
+// the archive, so Close must be called to finish the file.
+type XLSXWriter struct {
+	// SheetName is the name of the sheet, "Sheet1" by default
+	SheetName string
+	// DateFormat is the Excel number format of time cells, "yyyy-mm-dd hh:mm:ss" by default
+	DateFormat string
+	// NoHeader skips the header row
+	NoHeader bool
+
+	out     io.Writer
+	zw      *zip.Writer
+	sheet   *bufio.Writer
+	columns []string
+	row     int
+}
+
+// NewXLSXWriter - returns an XLSXWriter writing to w
+func NewXLSXWriter(w io.Writer) *XLSXWriter {
+	return &XLSXWriter{SheetName: "Sheet1", DateFormat: "yyyy-mm-dd hh:mm:ss", out: w}
+}
+
+// Write - writes a row, preceded by the header on the first call
+func (xw *XLSXWriter) Write(dr *DBResult) error {
+	if xw.zw == nil {
+		if err := xw.start(dr.Fields()); err != nil {
+			return err
+		}
+	}
+
+	xw.row++
+	fmt.Fprintf(xw.sheet, `<row r="%d">`, xw.row)
+	for i, column := range xw.columns {
+		if dr.IsNull(column) || !dr.Has(column) {
+			continue
+		}
+		xw.writeCell(cellRef(i, xw.row), dr.Get(column))
+	}
+	_, err := xw.sheet.WriteString(`</row>`)
+	return err
+}
+
+// WriteAll - writes every row and closes the workbook
+func (xw *XLSXWriter) WriteAll(rs ResultSet) error {
+	for _, dr := range rs {
+		if err := xw.Write(dr); err != nil {
+			return err
+		}
+	}
+	return xw.Close()
+}
+
+// Close - finishes the sheet and the archive; the underlying writer is not closed
+func (xw *XLSXWriter) Close() error {
+	if xw.zw == nil {
+		if err := xw.start(nil); err != nil {
+			return err
+		}
+	}
+
+	xw.sheet.WriteString(`</sheetData></worksheet>`)
+	if err := xw.sheet.Flush(); err != nil {
+		return err
+	}
+	return xw.zw.Close()
+}
+
+// start - writes the workbook parts and opens the sheet with the column widths and the header
+func (xw *XLSXWriter) start(fields []Field) error {
+	xw.zw = zip.NewWriter(xw.out)
+
+	parts := []struct{ name, body string }{
+		{"[Content_Types].xml", xlsxContentTypes},
+		{"_rels/.rels", xlsxRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, xmlAttr(xw.SheetName))},
+		{"xl/_rels/workbook.xml.rels", xlsxWorkbookRels},
+		{"xl/styles.xml", fmt.Sprintf(xlsxStyles, xmlAttr(xw.DateFormat))},
+	}
+	for _, part := range parts {
+		f, err := xw.zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, xml.Header+part.body); err != nil {
+			return err
+		}
+	}
+
+	f, err := xw.zw.Create("xl/worksheets/sheet1.xml")
+	if err != nil {
+		return err
+	}
+	xw.sheet = bufio.NewWriter(f)
+	xw.sheet.WriteString(xml.Header)
+	xw.sheet.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+
+	if len(fields) > 0 {
+		xw.sheet.WriteString(`<cols>`)
+		for i, field := range fields {
+			fmt.Fprintf(xw.sheet, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, columnWidth(field))
+		}
+		xw.sheet.WriteString(`</cols>`)
+	}
+	xw.sheet.WriteString(`<sheetData>`)
+
+	for _, field := range fields {
+		xw.columns = append(xw.columns, field.Column)
+	}
+	if xw.NoHeader || len(fields) == 0 {
+		return nil
+	}
+
+	xw.row++
+	fmt.Fprintf(xw.sheet, `<row r="%d">`, xw.row)
+	for i, field := range fields {
+		style := xlsxStyleHeader
+		if isNumeric(field.Type) {
+			style = xlsxStyleHeaderRight
+		}
+		xw.inlineString(cellRef(i, xw.row), field.Column, style)
+	}
+	_, err = xw.sheet.WriteString(`</row>`)
+	return err
+}
+
+// writeCell - writes a non-NULL value as a typed cell
+func (xw *XLSXWriter) writeCell(ref string, val any) {
+	switch v := val.(type) {
+	case bool:
+		b := 0
+		if v {
+			b = 1
+		}
+		fmt.Fprintf(xw.sheet, `<c r="%s" t="b"><v>%d</v></c>`, ref, b)
+		return
+	case time.Time:
+		fmt.Fprintf(xw.sheet, `<c r="%s" s="%d"><v>%s</v></c>`, ref, xlsxStyleDate,
+			strconv.FormatFloat(excelSerial(v), 'f', -1, 64))
+		return
+	case []byte:
+		xw.inlineString(ref, base64.StdEncoding.EncodeToString(v), xlsxStyleDefault)
+		return
+	}
+
+	rv := reflect.ValueOf(val)
+	switch {
+	case rv.CanInt():
+		fmt.Fprintf(xw.sheet, `<c r="%s"><v>%d</v></c>`, ref, rv.Int())
+	case rv.CanUint():
+		fmt.Fprintf(xw.sheet, `<c r="%s"><v>%d</v></c>`, ref, rv.Uint())
+	case rv.CanFloat() && !math.IsNaN(rv.Float()) && !math.IsInf(rv.Float(), 0):
+		fmt.Fprintf(xw.sheet, `<c r="%s"><v>%s</v></c>`, ref, strconv.FormatFloat(rv.Float(), 'f', -1, 64))
+	default:
+		xw.inlineString(ref, formatValue(val, ""), xlsxStyleDefault)
+	}
+}
+
+// inlineString - writes a text cell
+func (xw *XLSXWriter) inlineString(ref, text string, style int) {
+	fmt.Fprintf(xw.sheet, `<c r="%s" t="inlineStr"`, ref)
+	if style != xlsxStyleDefault {
+		fmt.Fprintf(xw.sheet, ` s="%d"`, style)
+	}
+	xw.sheet.WriteString(`><is><t xml:space="preserve">`)
+	xml.EscapeText(xw.sheet, []byte(text))
+	xw.sheet.WriteString(`</t></is></c>`)
+}
+
+// cellRef - returns the A1 reference of a zero based column and a one based row
+func cellRef(col, row int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return name + strconv.Itoa(row)
+}
+
+// excelSerial - returns the Excel date serial of a time, keeping its wall clock
+func excelSerial(t time.Time) float64 {
+	epoch := time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+	wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+	return wall.Sub(epoch).Hours() / 24
+}
+
+// columnWidth - estimates the width of a column in characters from its field type
+func columnWidth(field Field) int {
+	width := 30
+	switch {
+	case field.Type == boolType:
+		width = 7
+	case field.Type == reflect.TypeOf(time.Time{}):
+		width = 20
+	case isFloat(field.Type):
+		width = 14
+	case isNumeric(field.Type):
+		width = 12
+	}
+	return max(width, len(field.Column)+2)
+}
+
+// xmlAttr - escapes text for use in an attribute value
+func xmlAttr(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+const xlsxContentTypes = `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`</Types>`
+
+const xlsxRels = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbook = `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+	`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets><sheet name="%s" sheetId="1" r:id="rId1"/></sheets></workbook>`
+
+const xlsxWorkbookRels = `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+	`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+// xlsxStyles - cellXfs are in the order of the xlsxStyle constants
+const xlsxStyles = `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="1"><numFmt numFmtId="164" formatCode="%s"/></numFmts>` +
+	`<fonts count="2"><font><sz val="11"/><name val="Calibri"/></font><font><b/><sz val="11"/><name val="Calibri"/></font></fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="4">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1" applyAlignment="1"><alignment horizontal="right"/></xf>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
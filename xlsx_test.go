@@ -0,0 +1,105 @@
+package godyno
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+func TestXLSXWriter(t *testing.T) {
+	db, mock := setupMockDB(t)
+	defer db.Close()
+
+	created := time.Date(2024, 1, 15, 12, 0, 0, 0, time.UTC)
+	mock.ExpectQuery("SELECT").WillReturnRows(
+		sqlmock.NewRows([]string{"id", "title", "price", "active", "created_at", "address.city"}).
+			AddRow(1, "Desk & <oak>", 199.5, true, created, "Izmir").
+			AddRow(2, "Lamp", 19.9, false, created, nil),
+	)
+	results, err := QueryToStruct(db, "SELECT * FROM products")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	w := NewXLSXWriter(&buf)
+	w.SheetName = "Products"
+	if err := w.WriteAll(results); err != nil {
+		t.Fatalf("WriteAll() error = %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Invalid zip: %v", err)
+	}
+
+	parts := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("Open(%s) error = %v", f.Name, err)
+		}
+		b, _ := io.ReadAll(rc)
+		rc.Close()
+		parts[f.Name] = string(b)
+
+		// every part must be well-formed XML
+		dec := xml.NewDecoder(bytes.NewReader(b))
+		for {
+			if _, err := dec.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%s is not well-formed: %v", f.Name, err)
+			}
+		}
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/styles.xml", "xl/worksheets/sheet1.xml"} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("Missing part %s", name)
+		}
+	}
+	if !strings.Contains(parts["xl/workbook.xml"], `name="Products"`) {
+		t.Errorf("Sheet name not set: %s", parts["xl/workbook.xml"])
+	}
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	for _, want := range []string{
+		`<c r="A1" t="inlineStr" s="2"><is><t xml:space="preserve">id</t></is></c>`,
+		`<c r="B1" t="inlineStr" s="1"><is><t xml:space="preserve">title</t></is></c>`,
+		`<c r="F1" t="inlineStr" s="1"><is><t xml:space="preserve">address.city</t></is></c>`,
+		`<c r="A2"><v>1</v></c>`,
+		`<t xml:space="preserve">Desk &amp; &lt;oak&gt;</t>`,
+		`<c r="C2"><v>199.5</v></c>`,
+		`<c r="D2" t="b"><v>1</v></c>`,
+		`<c r="E2" s="3"><v>45306.5</v></c>`,
+		`<c r="D3" t="b"><v>0</v></c>`,
+		`<col min="4" max="4" width="8" customWidth="1"/>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("Sheet does not contain %s", want)
+		}
+	}
+	if strings.Contains(sheet, `r="F3"`) {
+		t.Error("NULL value should not produce a cell")
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("Unfulfilled expectations: %s", err)
+	}
+}
+
+func TestCellRef(t *testing.T) {
+	tests := map[int]string{0: "A1", 25: "Z1", 26: "AA1", 51: "AZ1", 52: "BA1", 701: "ZZ1", 702: "AAA1"}
+	for col, want := range tests {
+		if got := cellRef(col, 1); got != want {
+			t.Errorf("cellRef(%d, 1) = %s, want %s", col, got, want)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package godyno
+
+import (
+	"encoding/xml"
+	"io"
+	"reflect"
+	"time"
+)
+
+// MarshalXML - encodes the result as an element per field in column order,
+// nested structs as child elements. NULL columns are written as empty
+// elements with a null="true" attribute and []byte values as base64 text.
+func (dr *DBResult) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
+	return dr.encodeXML(e, start, time.RFC3339Nano)
+}
+
+// encodeXML - writes the result as the element start, times formatted with layout
+func (dr *DBResult) encodeXML(e *xml.Encoder, start xml.StartElement, layout string) (err error) {
+	defer recoverError(&err, ErrReflection, "")
+
+	if err := e.EncodeToken(start); err != nil {
+		return err
+	}
+	if dr != nil {
+		if err := dr.writeXML(e, reflect.ValueOf(dr.value), "", layout); err != nil {
+			return err
+		}
+	}
+	return e.EncodeToken(start.End())
+}
+
+// writeXML - writes the fields of one (possibly nested) struct level as elements
+func (dr *DBResult) writeXML(e *xml.Encoder, v reflect.Value, prefix, layout string) error {
+	if !v.IsValid() {
+		return nil
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		name := fieldKey(sf)
+		path := prefix + name
+		start := xml.StartElement{Name: xml.Name{Local: name}}
+
+		if isNested(sf.Type) {
+			if err := e.EncodeToken(start); err != nil {
+				return err
+			}
+			if err := dr.writeXML(e, v.Field(i), path+".", layout); err != nil {
+				return err
+			}
+			if err := e.EncodeToken(start.End()); err != nil {
+				return err
+			}
+			continue
+		}
+
+		text := ""
+		if dr.IsNull(path) {
+			start.Attr = []xml.Attr{{Name: xml.Name{Local: "null"}, Value: "true"}}
+		} else {
+			text = formatValue(v.Field(i).Interface(), layout)
+		}
+
+		if err := e.EncodeElement(text, start); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// XMLEncoder - writes results as XML documents
+type XMLEncoder struct {
+	// RootName is the element wrapping the rows in EncodeAll, "results" by default
+	RootName string
+	// RowName is the element of a row, "row" by default
+	RowName string
+	// Indent indents nested elements when not empty
+	Indent string
+	// TimeFormat is the layout for time.Time values, time.RFC3339Nano by default
+	TimeFormat string
+
+	out io.Writer
+}
+
+// NewXMLEncoder - returns an XMLEncoder writing to w
+func NewXMLEncoder(w io.Writer) *XMLEncoder {
+	return &XMLEncoder{RootName: "results", RowName: "row", TimeFormat: time.RFC3339Nano, out: w}
+}
+
+// Encode - writes a single row element
+func (xe *XMLEncoder) Encode(dr *DBResult) error {
+	e := xe.encoder()
+	if err := dr.encodeXML(e, xml.StartElement{Name: xml.Name{Local: xe.RowName}}, xe.TimeFormat); err != nil {
+		return err
+	}
+	return e.Close()
+}
+
+// EncodeAll - writes an XML document with every row inside the root element
+func (xe *XMLEncoder) EncodeAll(rs ResultSet) error {
+	if _, err := io.WriteString(xe.out, xml.Header); err != nil {
+		return err
+	}
+
+	e := xe.encoder()
+	root := xml.StartElement{Name: xml.Name{Local: xe.RootName}}
+	if err := e.EncodeToken(root); err != nil {
+		return err
+	}
+	for _, dr := range rs {
+		if err := dr.encodeXML(e, xml.StartElement{Name: xml.Name{Local: xe.RowName}}, xe.TimeFormat); err != nil {
+			return err
+		}
+	}
+	if err := e.EncodeToken(root.End()); err != nil {
+		return err
+	}
+	return e.Close()
+}
+
+// encoder - returns an xml.Encoder with the configured indentation
+func (xe *XMLEncoder) encoder() *xml.Encoder {
+	e := xml.NewEncoder(xe.out)
+	if xe.Indent != "" {
+		e.Indent("", xe.Indent)
+	}
+	return e
+}
@@ -0,0 +1,54 @@
+package godyno
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestXMLEncoder(t *testing.T) {
+	created := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	rs := newTestSet(t,
+		map[string]any{"id": int64(1), "title": "Desk & <oak>", "created_at": created, "address.city": "Izmir"},
+		map[string]any{"id": int64(2), "title": "Lamp", "created_at": created, "address.city": nil},
+	)
+
+	var b strings.Builder
+	enc := NewXMLEncoder(&b)
+	enc.RootName = "products"
+	enc.RowName = "product"
+	if err := enc.EncodeAll(rs); err != nil {
+		t.Fatalf("EncodeAll() error = %v", err)
+	}
+
+	want := xml.Header + `<products>` +
+		`<product><address><city>Izmir</city></address><created_at>2024-01-15T10:30:00Z</created_at><id>1</id><title>Desk &amp; &lt;oak&gt;</title></product>` +
+		`<product><address><city null="true"></city></address><created_at>2024-01-15T10:30:00Z</created_at><id>2</id><title>Lamp</title></product>` +
+		`</products>`
+	if b.String() != want {
+		t.Errorf("EncodeAll() =\n%s\nwant\n%s", b.String(), want)
+	}
+
+	t.Run("indent and single row", func(t *testing.T) {
+		var b strings.Builder
+		enc := NewXMLEncoder(&b)
+		enc.Indent = "  "
+		if err := enc.Encode(rs[1]); err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+		if !strings.HasPrefix(b.String(), "<row>\n  <address>\n    <city null=\"true\"></city>\n  </address>") {
+			t.Errorf("Encode() =\n%s", b.String())
+		}
+	})
+
+	t.Run("xml.Marshal", func(t *testing.T) {
+		b, err := xml.Marshal(rs[0])
+		if err != nil {
+			t.Fatalf("xml.Marshal() error = %v", err)
+		}
+		if !strings.HasPrefix(string(b), "<DBResult><address><city>Izmir</city></address>") {
+			t.Errorf("xml.Marshal() = %s", b)
+		}
+	})
+}
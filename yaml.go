@@ -0,0 +1,198 @@
+package godyno
+
+import (
+	"bufio"
+	"encoding/base64"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// yamlReserved - plain scalars YAML parsers read as something other than a string
+var yamlReserved = map[string]bool{
+	"true": true, "false": true, "yes": true, "no": true, "on": true, "off": true,
+	"y": true, "n": true, "null": true, "~": true,
+}
+
+// YAMLEncoder - writes results as YAML in column order. NULL columns are
+// written as null, nested structs as nested mappings and []byte values as
+// !!binary scalars. Strings are only quoted when they would be misread.
+type YAMLEncoder struct {
+	// TimeFormat is the layout for time.Time values, time.RFC3339Nano by default
+	TimeFormat string
+
+	out  io.Writer
+	docs int
+}
+
+// NewYAMLEncoder - returns a YAMLEncoder writing to w
+func NewYAMLEncoder(w io.Writer) *YAMLEncoder {
+	return &YAMLEncoder{TimeFormat: time.RFC3339Nano, out: w}
+}
+
+// Encode - writes a row as a mapping; documents after the first are preceded by "---"
+func (ye *YAMLEncoder) Encode(dr *DBResult) error {
+	lines, err := ye.lines(dr)
+	if err != nil {
+		return err
+	}
+
+	w := bufio.NewWriter(ye.out)
+	if ye.docs > 0 {
+		w.WriteString("---\n")
+	}
+	ye.docs++
+
+	if len(lines) == 0 {
+		w.WriteString("{}\n")
+	}
+	for _, line := range lines {
+		w.WriteString(line + "\n")
+	}
+	return w.Flush()
+}
+
+// EncodeAll - writes every row as one sequence of mappings
+func (ye *YAMLEncoder) EncodeAll(rs ResultSet) error {
+	w := bufio.NewWriter(ye.out)
+	if len(rs) == 0 {
+		w.WriteString("[]\n")
+	}
+
+	for _, dr := range rs {
+		lines, err := ye.lines(dr)
+		if err != nil {
+			return err
+		}
+		if len(lines) == 0 {
+			w.WriteString("- {}\n")
+			continue
+		}
+		for i, line := range lines {
+			if i == 0 {
+				w.WriteString("- " + line + "\n")
+			} else {
+				w.WriteString("  " + line + "\n")
+			}
+		}
+	}
+
+	return w.Flush()
+}
+
+// lines - returns the mapping lines of a row without a trailing newline
+func (ye *YAMLEncoder) lines(dr *DBResult) (lines []string, err error) {
+	defer recoverError(&err, ErrReflection, "")
+
+	if dr == nil {
+		return nil, nil
+	}
+	ye.writeYAML(&lines, dr, reflect.ValueOf(dr.value), "", "")
+	return lines, nil
+}
+
+// writeYAML - appends one (possibly nested) struct level as mapping lines
+func (ye *YAMLEncoder) writeYAML(lines *[]string, dr *DBResult, v reflect.Value, prefix, indent string) {
+	if !v.IsValid() {
+		return
+	}
+
+	for i := 0; i < v.NumField(); i++ {
+		sf := v.Type().Field(i)
+		name := fieldKey(sf)
+		path := prefix + name
+		key := indent + yamlString(name) + ":"
+
+		if isNested(sf.Type) {
+			*lines = append(*lines, key)
+			ye.writeYAML(lines, dr, v.Field(i), path+".", indent+"  ")
+			continue
+		}
+
+		if dr.IsNull(path) {
+			*lines = append(*lines, key+" null")
+			continue
+		}
+		*lines = append(*lines, key+" "+ye.scalar(v.Field(i).Interface()))
+	}
+}
+
+// scalar - returns the YAML text of a non-NULL value
+func (ye *YAMLEncoder) scalar(val any) string {
+	switch v := val.(type) {
+	case string:
+		return yamlString(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case []byte:
+		return "!!binary " + base64.StdEncoding.EncodeToString(v)
+	case time.Time:
+		// Only real times are written as plain timestamps, strings that
+		// look like dates stay quoted so they read back as strings
+		s := v.Format(ye.TimeFormat)
+		if isTimestamp(s) {
+			return s
+		}
+		return yamlString(s)
+	}
+
+	rv := reflect.ValueOf(val)
+	if rv.CanFloat() {
+		f := rv.Float()
+		switch {
+		case math.IsNaN(f):
+			return ".nan"
+		case math.IsInf(f, 1):
+			return ".inf"
+		case math.IsInf(f, -1):
+			return "-.inf"
+		}
+	}
+	if rv.CanInt() || rv.CanUint() || rv.CanFloat() {
+		return formatValue(val, "")
+	}
+	return yamlString(formatValue(val, ""))
+}
+
+// yamlString - returns s as a plain scalar when it reads back as the same
+// string, otherwise double quoted
+func yamlString(s string) string {
+	if yamlPlain(s) {
+		return s
+	}
+	return strconv.Quote(s)
+}
+
+// yamlPlain - reports whether s can be written without quotes: it starts with a
+// letter or underscore, uses no YAML indicators and is not a reserved word.
+// Date-like strings start with a digit and are therefore always quoted.
+func yamlPlain(s string) bool {
+	if s == "" || yamlReserved[strings.ToLower(s)] || strings.HasSuffix(s, " ") {
+		return false
+	}
+	for i, r := range s {
+		switch {
+		case unicode.IsLetter(r) || r == '_':
+		case i == 0:
+			return false
+		case unicode.IsDigit(r) || r == ' ' || r == '-' || r == '.' || r == '/' || r == '@':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// isTimestamp - reports whether s is a date or an RFC 3339 timestamp
+func isTimestamp(s string) bool {
+	for _, layout := range []string{time.DateOnly, time.RFC3339Nano} {
+		if _, err := time.Parse(layout, s); err == nil {
+			return true
+		}
+	}
+	return false
+}
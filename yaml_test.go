@@ -0,0 +1,65 @@
+package godyno
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestYAMLEncoder(t *testing.T) {
+	created := time.Date(2024, 1, 15, 10, 30, 0, 0, time.UTC)
+	rs := newTestSet(t,
+		map[string]any{"id": int64(1), "title": "Desk: oak", "code": "01234", "day": "2024-01-15", "active": true, "price": 199.5, "created_at": created, "address.city": "Izmir", "address.zip": []byte{0xff, 0x01}},
+		map[string]any{"id": int64(2), "title": "yes", "code": "", "day": "2024-01-15T10:30:00Z", "active": false, "price": 19.9, "created_at": created, "address.city": nil, "address.zip": nil},
+	)
+
+	var b strings.Builder
+	if err := NewYAMLEncoder(&b).EncodeAll(rs); err != nil {
+		t.Fatalf("EncodeAll() error = %v", err)
+	}
+
+	want := `- active: true
+  address:
+    city: Izmir
+    zip: !!binary /wE=
+  code: "01234"
+  created_at: 2024-01-15T10:30:00Z
+  day: "2024-01-15"
+  id: 1
+  price: 199.5
+  title: "Desk: oak"
+- active: false
+  address:
+    city: null
+    zip: null
+  code: ""
+  created_at: 2024-01-15T10:30:00Z
+  day: "2024-01-15T10:30:00Z"
+  id: 2
+  price: 19.9
+  title: "yes"
+`
+	if b.String() != want {
+		t.Errorf("EncodeAll() =\n%s\nwant\n%s", b.String(), want)
+	}
+
+	t.Run("documents", func(t *testing.T) {
+		var b strings.Builder
+		enc := NewYAMLEncoder(&b)
+		for _, dr := range rs {
+			if err := enc.Encode(dr); err != nil {
+				t.Fatalf("Encode() error = %v", err)
+			}
+		}
+		if !strings.HasPrefix(b.String(), "active: true\naddress:\n  city: Izmir\n") || strings.Count(b.String(), "---\n") != 1 {
+			t.Errorf("Encode() =\n%s", b.String())
+		}
+	})
+
+	t.Run("empty", func(t *testing.T) {
+		var b strings.Builder
+		if err := NewYAMLEncoder(&b).EncodeAll(nil); err != nil || b.String() != "[]\n" {
+			t.Errorf("EncodeAll(nil) = %q, %v", b.String(), err)
+		}
+	})
+}